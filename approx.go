@@ -0,0 +1,156 @@
+package mapify
+
+import (
+	"math"
+)
+
+// ApproxDedup returns a slice containing the elements of the provided slice in
+// their original order, with the probable duplicates removed, keeping the first
+// occurrence of each element. Elements are identified by the value returned by
+// the fingerprint function, which is tracked in a Bloom filter sized for
+// expectedN distinct elements and the false-positive rate fpRate. This bounds
+// the memory used regardless of the number of distinct elements, at the cost of
+// accuracy: an element that is not a duplicate is dropped with a probability of
+// roughly fpRate, and that probability grows once more than expectedN distinct
+// elements have been seen. Elements with identical fingerprints are always
+// treated as duplicates. If fpRate is not between 0 and 1 exclusively, a rate
+// of 0.01 is used instead.
+func ApproxDedup[E any](s []E, fingerprint func(e E) uint64, expectedN int, fpRate float64) []E {
+	filter := newBloomFilter(expectedN, fpRate)
+	result := make([]E, 0, len(s))
+
+	for _, e := range s {
+		if filter.testAndAdd(fingerprint(e)) {
+			continue
+		}
+
+		result = append(result, e)
+	}
+
+	return result
+}
+
+// bloomFilter is a Bloom filter of 64-bit fingerprints, which derives its k bit
+// positions from a fingerprint using double hashing.
+type bloomFilter struct {
+	bits []uint64
+	m    uint64
+	k    int
+}
+
+// newBloomFilter creates a bloomFilter with the optimal number of bits and
+// hash functions for n elements and the false-positive rate p.
+func newBloomFilter(n int, p float64) *bloomFilter {
+	if n < 1 {
+		n = 1
+	}
+
+	if p <= 0 || p >= 1 {
+		p = 0.01
+	}
+
+	m := uint64(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	if m < 64 {
+		m = 64
+	}
+
+	k := int(math.Round(float64(m) / float64(n) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+
+	return &bloomFilter{
+		bits: make([]uint64, (m+63)/64),
+		m:    m,
+		k:    k,
+	}
+}
+
+// testAndAdd adds the fingerprint h to the filter and reports whether it was
+// probably already present.
+func (f *bloomFilter) testAndAdd(h uint64) bool {
+	present := true
+
+	f.positions(h, func(word uint64, mask uint64) {
+		if f.bits[word]&mask == 0 {
+			present = false
+			f.bits[word] |= mask
+		}
+	})
+
+	return present
+}
+
+// positions calls the function fn with the word index and bit mask of each of
+// the k bits of the filter that correspond to the fingerprint h.
+func (f *bloomFilter) positions(h uint64, fn func(word uint64, mask uint64)) {
+	h1 := mix64(h)
+	h2 := mix64(h1) | 1
+
+	for i := 0; i < f.k; i++ {
+		bit := (h1 + uint64(i)*h2) % f.m
+		fn(bit/64, uint64(1)<<(bit%64))
+	}
+}
+
+// mix64 scrambles the bits of x using the SplitMix64 finalizer, so that
+// fingerprints with little entropy still spread across the filter.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+
+	return x
+}
+
+// ApproxCountByKey counts the elements of the provided slice per key, as
+// determined by the key function, using a Count-Min Sketch of depth rows of
+// width counters, and returns a function that queries the approximate count of a
+// key. The memory used is bounded by width and depth regardless of the number of
+// distinct keys. Since keys can share counters, the approximate count is never
+// less than the true count but can exceed it: with a total of n elements, the
+// over-count is at most e*n/width with a probability of at least 1-exp(-depth).
+// The hashes are used for the rows in turn, each row scrambling the hash with
+// its own seed, so fewer hashes than rows can be provided. At least one hash
+// is required, and ApproxCountByKey panics if none is provided: the error bound
+// only holds when distinct keys have distinct hashes, which no generic hash
+// can guarantee for every key type, for instance pointers to equal structs.
+// If width or depth is less than one, one is used instead.
+func ApproxCountByKey[E any, K comparable](s []E, key func(e E) K, width, depth int, hashes []func(k K) uint64) func(k K) uint64 {
+	width, depth = max(width, 1), max(depth, 1)
+
+	if len(hashes) == 0 {
+		panic("mapify: ApproxCountByKey requires at least one hash")
+	}
+
+	counters := make([][]uint64, depth)
+	for i := range counters {
+		counters[i] = make([]uint64, width)
+	}
+
+	column := func(row int, k K) int {
+		h := hashes[row%len(hashes)](k)
+
+		return int(mix64(h+uint64(row)*0x9e3779b97f4a7c15) % uint64(width))
+	}
+
+	for _, e := range s {
+		k := key(e)
+
+		for row := range counters {
+			counters[row][column(row, k)]++
+		}
+	}
+
+	return func(k K) uint64 {
+		estimate := uint64(math.MaxUint64)
+
+		for row := range counters {
+			estimate = min(estimate, counters[row][column(row, k)])
+		}
+
+		return estimate
+	}
+}
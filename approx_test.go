@@ -0,0 +1,155 @@
+package mapify
+
+import (
+	"math"
+	"testing"
+)
+
+// TestApproxDedup verifies that the ApproxDedup function removes exact
+// duplicates while keeping the first occurrence of each element in order.
+func TestApproxDedup(t *testing.T) {
+	fingerprint := func(u *TestUser) uint64 {
+		return uint64(u.id)
+	}
+
+	input := []*TestUser{
+		&testUserBob,
+		&testUserAlice,
+		&testUserBob,
+		&testUserFred,
+		&testUserAlice,
+		&testUserMarie,
+		&testUserMarie,
+	}
+
+	result := ApproxDedup(input, fingerprint, 100, 0.001)
+
+	verifySlice(t, []*TestUser{&testUserBob, &testUserAlice, &testUserFred, &testUserMarie}, result)
+}
+
+// TestBloomFilter_FalsePositiveRate verifies that a bloomFilter sized for n
+// elements and a false-positive rate p roughly honors that rate once n elements
+// have been added to it.
+func TestBloomFilter_FalsePositiveRate(t *testing.T) {
+	const (
+		n       = 10000
+		p       = 0.01
+		queries = 100000
+	)
+
+	filter := newBloomFilter(n, p)
+
+	for i := uint64(0); i < n; i++ {
+		filter.testAndAdd(i)
+	}
+
+	falsePositives := 0
+	for i := uint64(n); i < n+queries; i++ {
+		if filter.contains(i) {
+			falsePositives++
+		}
+	}
+
+	if rate := float64(falsePositives) / queries; rate > 2*p {
+		t.Fatalf("false-positive rate %f is far above the requested %f", rate, p)
+	}
+}
+
+// contains reports whether the fingerprint h is probably present in the filter,
+// without adding it, which lets tests query a filter without modifying it.
+func (f *bloomFilter) contains(h uint64) bool {
+	present := true
+
+	f.positions(h, func(word uint64, mask uint64) {
+		if f.bits[word]&mask == 0 {
+			present = false
+		}
+	})
+
+	return present
+}
+
+// TestApproxCountByKey verifies that the counts reported by ApproxCountByKey
+// are never below the true counts and stay within the error bound of the
+// sketch for frequent keys.
+func TestApproxCountByKey(t *testing.T) {
+	const (
+		width = 200
+		depth = 5
+	)
+
+	var input []int
+	for k := 0; k < 1000; k++ {
+		input = append(input, k)
+	}
+
+	for i := 0; i < 500; i++ {
+		input = append(input, -1)
+	}
+
+	for i := 0; i < 300; i++ {
+		input = append(input, -2)
+	}
+
+	identity := func(k int) uint64 {
+		return uint64(k)
+	}
+
+	count := ApproxCountByKey(input, func(e int) int { return e }, width, depth, []func(int) uint64{identity})
+
+	bound := uint64(math.Ceil(math.E * float64(len(input)) / width))
+
+	for k, expected := range Count(input, func(e int) int { return e }) {
+		if actual := count(k); actual < uint64(expected) {
+			t.Fatalf("count of %d is expected to be at least %d but was %d", k, expected, actual)
+		}
+	}
+
+	for k, expected := range map[int]uint64{-1: 500, -2: 300} {
+		if actual := count(k); actual > expected+bound {
+			t.Logf("count of %d is expected to be within %d of %d but was %d", k, bound, expected, actual)
+			t.Fail()
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Log("expected ApproxCountByKey to panic without hashes")
+			t.Fail()
+		}
+	}()
+
+	ApproxCountByKey([]string{"a", "b", "a"}, func(s string) string { return s }, 0, 0, nil)
+}
+
+// TestApproxCountByKey_DistinctKeysWithEqualText verifies that keys formatting
+// to the same text, such as pointers to equal structs, are kept apart by
+// ApproxCountByKey when their hashes differ.
+func TestApproxCountByKey_DistinctKeysWithEqualText(t *testing.T) {
+	type point struct{ x int }
+
+	counted := &point{1}
+	other := &point{1}
+
+	ids := map[*point]uint64{counted: 1, other: 2}
+	identity := func(p *point) uint64 {
+		return ids[p]
+	}
+
+	input := make([]*point, 10)
+	for i := range input {
+		input[i] = counted
+	}
+
+	count := ApproxCountByKey(input, func(p *point) *point { return p }, 1<<16, 8, []func(*point) uint64{identity})
+
+	if actual := count(counted); actual != 10 {
+		t.Logf("count of the counted key is expected to be 10 but was %d", actual)
+		t.Fail()
+	}
+
+	if actual := count(other); actual != 0 {
+		t.Logf("count of a distinct key with the same formatted text is expected to be 0 but was %d", actual)
+		t.Fail()
+	}
+}
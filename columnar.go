@@ -0,0 +1,53 @@
+package mapify
+
+import (
+	"slices"
+)
+
+// Columnar converts the provided slice of E elements into a columnar layout,
+// returning a map of column names to slices of values. Each entry of the
+// columns map names a column and provides the function that extracts its value
+// from an element. Every column slice has the same length as the provided
+// slice, and the values at the same index of each column come from the same
+// element. Note that storing the values as any boxes every value that is not
+// already an interface or a pointer, which costs an allocation per value, so
+// this layout trades performance for flexibility.
+func Columnar[E any](s []E, columns map[string]func(e E) any) map[string][]any {
+	m := make(map[string][]any, len(columns))
+
+	for name, extract := range columns {
+		column := make([]any, len(s))
+
+		for i, e := range s {
+			column[i] = extract(e)
+		}
+
+		m[name] = column
+	}
+
+	return m
+}
+
+// ColumnarChunks extracts a numeric column from the provided slice of E
+// elements, using the value function, and splits it into consecutive chunks of
+// chunkSize values, which suits vectorized processing of the column. Every
+// chunk holds chunkSize values except the final one, which holds the remaining
+// values and may be shorter. The chunks share a single backing array but their
+// capacity is limited to their length, so appending to one chunk never
+// overwrites the next. An empty slice results in an empty, non-nil slice of
+// chunks. If chunkSize is less than one, one is used instead.
+func ColumnarChunks[E any, N Numeric](s []E, value func(e E) N, chunkSize int) [][]N {
+	chunkSize = max(chunkSize, 1)
+
+	column := make([]N, len(s))
+	for i, e := range s {
+		column[i] = value(e)
+	}
+
+	chunks := make([][]N, 0, (len(s)+chunkSize-1)/chunkSize)
+	for chunk := range slices.Chunk(column, chunkSize) {
+		chunks = append(chunks, chunk)
+	}
+
+	return chunks
+}
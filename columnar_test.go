@@ -0,0 +1,103 @@
+package mapify
+
+import (
+	"testing"
+)
+
+// TestColumnar verifies that the Columnar function extracts one slice per
+// column, with values aligned to the rows of the provided slice.
+func TestColumnar(t *testing.T) {
+	input := []*TestUser{&testUserBob, &testUserAlice, &testUserFred}
+
+	result := Columnar(input, map[string]func(*TestUser) any{
+		"id":   func(u *TestUser) any { return u.id },
+		"name": func(u *TestUser) any { return u.name },
+	})
+
+	if len(result) != 2 {
+		t.Fatalf("expected 2 columns but got %d", len(result))
+	}
+
+	verifySlice(t, []any{1, 2, 3}, result["id"])
+	verifySlice(t, []any{"bob", "alice", "fred"}, result["name"])
+
+	empty := Columnar(nil, map[string]func(*TestUser) any{
+		"id": func(u *TestUser) any { return u.id },
+	})
+
+	if column, ok := empty["id"]; !ok || len(column) != 0 {
+		t.Fatalf("expected an empty id column but got %v", empty)
+	}
+}
+
+// TestColumnarChunks verifies that the ColumnarChunks function splits the
+// extracted column into chunks of the requested size, with a shorter final
+// chunk when the length of the slice is not a multiple of that size.
+func TestColumnarChunks(t *testing.T) {
+	input := []*TestUser{&testUserBob, &testUserAlice, &testUserFred, &testUserMarie}
+	value := func(u *TestUser) float64 { return float64(u.id) / 2 }
+
+	for _, tc := range []struct {
+		name      string
+		input     []*TestUser
+		chunkSize int
+		expected  [][]float64
+	}{
+		{
+			name:      "empty",
+			input:     nil,
+			chunkSize: 2,
+			expected:  [][]float64{},
+		},
+		{
+			name:      "multiple-of-chunk-size",
+			input:     input,
+			chunkSize: 2,
+			expected:  [][]float64{{0.5, 1}, {1.5, 2}},
+		},
+		{
+			name:      "shorter-final-chunk",
+			input:     input,
+			chunkSize: 3,
+			expected:  [][]float64{{0.5, 1, 1.5}, {2}},
+		},
+		{
+			name:      "single-chunk",
+			input:     input,
+			chunkSize: 10,
+			expected:  [][]float64{{0.5, 1, 1.5, 2}},
+		},
+		{
+			name:      "one-per-chunk",
+			input:     input[:2],
+			chunkSize: 1,
+			expected:  [][]float64{{0.5}, {1}},
+		},
+		{
+			name:      "zero-chunk-size",
+			input:     input[:2],
+			chunkSize: 0,
+			expected:  [][]float64{{0.5}, {1}},
+		},
+		{
+			name:      "negative-chunk-size",
+			input:     input[:2],
+			chunkSize: -3,
+			expected:  [][]float64{{0.5}, {1}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result := ColumnarChunks(tc.input, value, tc.chunkSize)
+			if result == nil {
+				t.Fatal("actual is expected to be not nil")
+			}
+
+			verifyPages(t, tc.expected, result)
+		})
+	}
+
+	chunks := ColumnarChunks(input, value, 2)
+	_ = append(chunks[0], 100)
+	verifySlice(t, []float64{1.5, 2}, chunks[1])
+
+}
@@ -0,0 +1,127 @@
+package mapify
+
+import (
+	"cmp"
+	"slices"
+)
+
+// GroupByFuzzy clusters the elements of the provided slice whose text, as
+// returned by the text function, is within maxDistance of each other according
+// to the distance function, such as an edit distance. The clustering is greedy
+// and done in a single pass: the first element of each cluster is its
+// representative, and each element joins the first cluster, in order of
+// creation, whose representative is within maxDistance of it, or starts a new
+// cluster otherwise. As a result, the clusters depend on the order of the
+// elements, and two elements within maxDistance of each other can still end up
+// in different clusters. Clusters and their elements are returned in the order
+// they were encountered.
+func GroupByFuzzy[E any](s []E, text func(e E) string, distance func(a, b string) int, maxDistance int) [][]E {
+	var (
+		clusters        [][]E
+		representatives []string
+	)
+
+	for _, e := range s {
+		t := text(e)
+
+		found := false
+		for i, r := range representatives {
+			if distance(r, t) <= maxDistance {
+				clusters[i] = append(clusters[i], e)
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			clusters = append(clusters, []E{e})
+			representatives = append(representatives, t)
+		}
+	}
+
+	return clusters
+}
+
+// CanonicalizeKeys groups the elements of the provided slice by key, as
+// determined by the key function, after collapsing the keys that are within
+// maxDistance edits of each other onto a canonical key, which helps clean up
+// noisy categorical keys such as spelling variants. The distinct keys are
+// clustered greedily from the most to the least frequent, ties being broken in
+// ascending order: each key joins the first canonical key within maxDistance of
+// it, or becomes a canonical key otherwise, so the canonical key of each
+// cluster is its most frequent key. It returns the grouping, keyed by canonical
+// key with the elements in the order of the provided slice, along with a map of
+// every original key to its canonical key. The distance between keys is the
+// Levenshtein distance, counting single-byte insertions, deletions and
+// substitutions.
+func CanonicalizeKeys[E any](s []E, key func(e E) string, maxDistance int) (map[string][]E, map[string]string) {
+	keys := make([]string, len(s))
+	for i, e := range s {
+		keys[i] = key(e)
+	}
+
+	counts := Count(keys, func(k string) string { return k })
+	distinct := Keys(counts)
+
+	slices.SortFunc(distinct, func(a, b string) int {
+		if c := cmp.Compare(counts[b], counts[a]); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a, b)
+	})
+
+	canonical := make(map[string]string, len(distinct))
+	representatives := make([]string, 0)
+
+	for _, k := range distinct {
+		i := slices.IndexFunc(representatives, func(r string) bool {
+			return levenshtein(r, k) <= maxDistance
+		})
+
+		if i < 0 {
+			representatives = append(representatives, k)
+			canonical[k] = k
+		} else {
+			canonical[k] = representatives[i]
+		}
+	}
+
+	groups := make(map[string][]E, len(representatives))
+	for i, e := range s {
+		c := canonical[keys[i]]
+
+		groups[c] = append(groups[c], e)
+	}
+
+	return groups, canonical
+}
+
+// levenshtein returns the minimum number of single-byte insertions, deletions
+// and substitutions needed to turn a into b.
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
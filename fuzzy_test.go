@@ -0,0 +1,108 @@
+package mapify
+
+import (
+	"testing"
+)
+
+// TestGroupByFuzzy verifies that the GroupByFuzzy function clusters the
+// elements with near-duplicate text and keeps distinct ones apart.
+func TestGroupByFuzzy(t *testing.T) {
+	input := []string{"color", "grey", "colour", "gray", "blue", "Color", "bleu"}
+
+	// hamming is a simple distance that compares the strings position by
+	// position and counts the length difference as differences too.
+	hamming := func(a, b string) int {
+		if len(a) > len(b) {
+			a, b = b, a
+		}
+
+		d := len(b) - len(a)
+		for i := range a {
+			if a[i] != b[i] {
+				d++
+			}
+		}
+
+		return d
+	}
+
+	result := GroupByFuzzy(input, func(s string) string { return s }, hamming, 2)
+
+	verifyPages(t, [][]string{
+		{"color", "colour", "Color"},
+		{"grey", "gray"},
+		{"blue", "bleu"},
+	}, result)
+
+	if exact := GroupByFuzzy(input, func(s string) string { return s }, hamming, 0); len(exact) != len(input) {
+		t.Fatalf("expected every element in its own cluster but got %v", exact)
+	}
+}
+
+// TestCanonicalizeKeys verifies that the CanonicalizeKeys function collapses
+// similar keys onto the most frequent one and keeps distinct keys apart.
+func TestCanonicalizeKeys(t *testing.T) {
+	type Item struct {
+		category string
+		name     string
+	}
+
+	input := []Item{
+		{category: "colour", name: "red"},
+		{category: "color", name: "green"},
+		{category: "size", name: "large"},
+		{category: "color", name: "blue"},
+		{category: "sise", name: "small"},
+		{category: "size", name: "medium"},
+		{category: "shape", name: "round"},
+	}
+
+	groups, canonical := CanonicalizeKeys(input, func(i Item) string {
+		return i.category
+	}, 1)
+
+	verifyResult(t, map[string]string{
+		"color":  "color",
+		"colour": "color",
+		"size":   "size",
+		"sise":   "size",
+		"shape":  "shape",
+	}, canonical)
+
+	names := func(items []Item) []string {
+		var n []string
+		for _, i := range items {
+			n = append(n, i.name)
+		}
+
+		return n
+	}
+
+	if len(groups) != 3 {
+		t.Fatalf("expected 3 keys but got %v", groups)
+	}
+
+	verifySlice(t, []string{"red", "green", "blue"}, names(groups["color"]))
+	verifySlice(t, []string{"large", "small", "medium"}, names(groups["size"]))
+	verifySlice(t, []string{"round"}, names(groups["shape"]))
+}
+
+// TestLevenshtein verifies the edit distances computed by levenshtein.
+func TestLevenshtein(t *testing.T) {
+	for _, tc := range []struct {
+		a, b     string
+		expected int
+	}{
+		{a: "", b: "", expected: 0},
+		{a: "abc", b: "", expected: 3},
+		{a: "", b: "abc", expected: 3},
+		{a: "color", b: "colour", expected: 1},
+		{a: "kitten", b: "sitting", expected: 3},
+		{a: "flaw", b: "lawn", expected: 2},
+	} {
+		if d := levenshtein(tc.a, tc.b); d != tc.expected {
+			t.Logf("distance between %q and %q is expected to be %d but was %d", tc.a, tc.b, tc.expected, d)
+			t.Fail()
+		}
+	}
+}
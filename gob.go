@@ -0,0 +1,37 @@
+package mapify
+
+import (
+	"encoding/gob"
+	"fmt"
+	"io"
+)
+
+// WriteGroupsGob encodes the provided map of grouped elements to w using
+// encoding/gob, so that it can later be restored with ReadGroupsGob. The order
+// of the elements within each slice is preserved. If K or E is an interface
+// type, the concrete types stored in the map must have been registered with
+// gob.Register beforehand.
+func WriteGroupsGob[K comparable, E any](w io.Writer, m map[K][]E) error {
+	if err := gob.NewEncoder(w).Encode(m); err != nil {
+		return fmt.Errorf("failed to encode groups: %w", err)
+	}
+
+	return nil
+}
+
+// ReadGroupsGob decodes a map of grouped elements written by WriteGroupsGob
+// from r. The same registration requirements as for WriteGroupsGob apply. The
+// returned map is never nil when the error is nil.
+func ReadGroupsGob[K comparable, E any](r io.Reader) (map[K][]E, error) {
+	var m map[K][]E
+
+	if err := gob.NewDecoder(r).Decode(&m); err != nil {
+		return nil, fmt.Errorf("failed to decode groups: %w", err)
+	}
+
+	if m == nil {
+		m = make(map[K][]E)
+	}
+
+	return m, nil
+}
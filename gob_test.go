@@ -0,0 +1,62 @@
+package mapify
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestGroupsGob_RoundTrip verifies that a map of grouped elements written by
+// WriteGroupsGob is restored identically by ReadGroupsGob, including the order
+// of the elements within each slice.
+func TestGroupsGob_RoundTrip(t *testing.T) {
+	type Record struct {
+		Name  string
+		Score int
+	}
+
+	for _, tc := range []struct {
+		name  string
+		input map[string][]Record
+	}{
+		{
+			name:  "empty-map",
+			input: map[string][]Record{},
+		},
+		{
+			name: "several-keys",
+			input: map[string][]Record{
+				"boys":  {{Name: "fred", Score: 3}, {Name: "bob", Score: 1}},
+				"girls": {{Name: "marie", Score: 4}, {Name: "alice", Score: 2}, {Name: "anna", Score: 5}},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			if err := WriteGroupsGob(&buf, tc.input); err != nil {
+				t.Fatalf("unexpected error writing groups: %v", err)
+			}
+
+			result, err := ReadGroupsGob[string, Record](&buf)
+			if err != nil {
+				t.Fatalf("unexpected error reading groups: %v", err)
+			}
+
+			if result == nil || len(result) != len(tc.input) {
+				t.Fatalf("expected %d keys but got %v", len(tc.input), result)
+			}
+
+			for k, v := range tc.input {
+				verifySlice(t, v, result[k])
+			}
+		})
+	}
+}
+
+// TestReadGroupsGob_Error verifies that ReadGroupsGob returns an error when the
+// input cannot be decoded.
+func TestReadGroupsGob_Error(t *testing.T) {
+	if _, err := ReadGroupsGob[string, int](bytes.NewBufferString("not gob")); err == nil {
+		t.Fatal("expected an error decoding invalid input")
+	}
+}
@@ -0,0 +1,25 @@
+package mapify
+
+// WeightedAdjacency creates a weighted adjacency map from the provided slice of
+// edges, using the from and to functions to determine the vertices each edge
+// connects and the weight function to determine its weight. The outer map is
+// keyed by the source vertex and the inner maps by the target vertex. The
+// weights of edges that connect the same pair of vertices in the same direction
+// are summed.
+func WeightedAdjacency[E any, K comparable](edges []E, from func(e E) K, to func(e E) K, weight func(e E) float64) map[K]map[K]float64 {
+	m := make(map[K]map[K]float64)
+
+	for _, e := range edges {
+		f := from(e)
+
+		targets, ok := m[f]
+		if !ok {
+			targets = make(map[K]float64)
+			m[f] = targets
+		}
+
+		targets[to(e)] += weight(e)
+	}
+
+	return m
+}
@@ -0,0 +1,36 @@
+package mapify
+
+import (
+	"testing"
+)
+
+// TestWeightedAdjacency verifies that the WeightedAdjacency function sums the
+// weights of parallel edges and keeps distinct edges separate.
+func TestWeightedAdjacency(t *testing.T) {
+	type Edge struct {
+		from, to string
+		weight   float64
+	}
+
+	input := []Edge{
+		{from: "a", to: "b", weight: 1.5},
+		{from: "a", to: "c", weight: 2},
+		{from: "b", to: "a", weight: 4},
+		{from: "a", to: "b", weight: 0.25},
+	}
+
+	result := WeightedAdjacency(input, func(e Edge) string {
+		return e.from
+	}, func(e Edge) string {
+		return e.to
+	}, func(e Edge) float64 {
+		return e.weight
+	})
+
+	if len(result) != 2 {
+		t.Fatalf("expected 2 source vertices but got %v", result)
+	}
+
+	verifyFloats(t, map[string]float64{"b": 1.75, "c": 2}, result["a"])
+	verifyFloats(t, map[string]float64{"a": 4}, result["b"])
+}
@@ -0,0 +1,330 @@
+package mapify
+
+import (
+	"cmp"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"slices"
+	"strconv"
+)
+
+// PageKeys returns a page of at most limit keys from the provided map of
+// grouped elements, such as one created by FromSliceWithDuplicates. The keys are
+// sorted in ascending order and only the keys strictly greater than afterKey are
+// considered, which allows the caller to walk through all of the keys of the map
+// by passing the returned nextCursor as the afterKey of the following call. The
+// first page is requested with FirstPageKeys, which also returns the keys that
+// are less than or equal to the zero value of K, such as negative numbers or the
+// empty string. The hasMore result indicates whether keys remain beyond the
+// returned page. If no keys are returned, nextCursor is equal to afterKey.
+func PageKeys[K cmp.Ordered, E any](m map[K][]E, afterKey K, limit int) (keys []K, nextCursor K, hasMore bool) {
+	return pageKeys(m, func(k K) bool { return k > afterKey }, afterKey, limit)
+}
+
+// FirstPageKeys returns the first page of at most limit keys from the provided
+// map of grouped elements, in the same way as PageKeys, except that every key
+// of the map is considered. The following pages are requested by passing the
+// returned nextCursor to PageKeys. If no keys are returned, nextCursor is the
+// zero value of K.
+func FirstPageKeys[K cmp.Ordered, E any](m map[K][]E, limit int) (keys []K, nextCursor K, hasMore bool) {
+	var zero K
+
+	return pageKeys(m, func(K) bool { return true }, zero, limit)
+}
+
+// pageKeys returns a page of at most limit keys, in ascending order, among the
+// keys of the provided map for which the include function returns true. If no
+// keys are returned, nextCursor is equal to cursor.
+func pageKeys[K cmp.Ordered, E any](m map[K][]E, include func(k K) bool, cursor K, limit int) (keys []K, nextCursor K, hasMore bool) {
+	candidates := make([]K, 0, len(m))
+
+	for k := range m {
+		if include(k) {
+			candidates = append(candidates, k)
+		}
+	}
+
+	slices.Sort(candidates)
+
+	if limit < 0 {
+		limit = 0
+	}
+
+	if len(candidates) <= limit {
+		keys = candidates
+	} else {
+		keys = candidates[:limit]
+		hasMore = true
+	}
+
+	nextCursor = cursor
+	if len(keys) > 0 {
+		nextCursor = keys[len(keys)-1]
+	}
+
+	return keys, nextCursor, hasMore
+}
+
+// ShuffleGroups shuffles the elements of each slice in the provided map of
+// grouped elements in place, using the provided random number generator. Since
+// map keys are visited in an unspecified order, each slice is shuffled by its
+// own generator whose seed combines a single value drawn from rng with a hash
+// of the slice's key. This makes the result reproducible for a given seed
+// regardless of the map iteration order, provided the Go-syntax representation
+// of the keys, as printed by the %#v verb, contains no addresses. Keys that are
+// or contain pointers, such as structs with pointer fields, print addresses
+// that change from one process to the next, so their slices are shuffled
+// differently in each process, although still consistently within a process.
+func ShuffleGroups[K comparable, E any](m map[K][]E, rng *rand.Rand) {
+	seed := rng.Int63()
+
+	for k, bucket := range m {
+		bucketRng := rand.New(rand.NewSource(seed ^ int64(hashKey(k))))
+
+		bucketRng.Shuffle(len(bucket), func(i, j int) {
+			bucket[i], bucket[j] = bucket[j], bucket[i]
+		})
+	}
+}
+
+// hashKey computes a hash of the Go-syntax representation of the provided key,
+// which is stable across map iterations, unlike the iteration order itself. It
+// is only stable across processes if that representation holds no addresses.
+func hashKey[K comparable](k K) uint64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%#v", k)
+
+	return h.Sum64()
+}
+
+// SortedGroupsByAggregate groups the elements of the provided slice in the same
+// way as FromSliceWithDuplicates and also returns the keys of the grouping
+// sorted in descending order of the aggregate computed by the agg function over
+// each key's slice of elements. Keys with equal aggregates are kept in the order
+// in which they first appear in the provided slice.
+func SortedGroupsByAggregate[E any, K comparable, A cmp.Ordered](s []E, key func(e E) K, agg func(group []E) A) ([]K, map[K][]E) {
+	m := make(map[K][]E)
+	keys := make([]K, 0)
+
+	for _, e := range s {
+		k := key(e)
+
+		if _, ok := m[k]; !ok {
+			keys = append(keys, k)
+		}
+
+		m[k] = append(m[k], e)
+	}
+
+	aggregates := make(map[K]A, len(m))
+	for k, v := range m {
+		aggregates[k] = agg(v)
+	}
+
+	slices.SortStableFunc(keys, func(a, b K) int {
+		return cmp.Compare(aggregates[b], aggregates[a])
+	})
+
+	return keys, m
+}
+
+// FilterInGroups removes the elements for which the keep function returns false
+// from the slices of the provided map of grouped elements, and removes the keys
+// whose slice becomes empty. The map is modified in place: each slice is
+// filtered within its own backing array, preserving the order of the elements
+// that are kept, so other references to these slices observe the change.
+func FilterInGroups[K comparable, E any](m map[K][]E, keep func(e E) bool) {
+	for k, bucket := range m {
+		bucket = slices.DeleteFunc(bucket, func(e E) bool {
+			return !keep(e)
+		})
+
+		if len(bucket) == 0 {
+			delete(m, k)
+		} else {
+			m[k] = bucket
+		}
+	}
+}
+
+// InterleaveGroups flattens the provided map of grouped elements into a single
+// slice by taking the elements of the slices in turns: the first element of
+// each slice, in ascending key order, followed by the second element of each
+// slice, and so on. Slices that run out of elements are skipped, so the tails
+// of the longer slices end up at the end of the result.
+func InterleaveGroups[K cmp.Ordered, E any](m map[K][]E) []E {
+	keys := make([]K, 0, len(m))
+	total := 0
+
+	for k, v := range m {
+		keys = append(keys, k)
+		total += len(v)
+	}
+
+	slices.Sort(keys)
+
+	result := make([]E, 0, total)
+
+	for round := 0; len(result) < total; round++ {
+		for _, k := range keys {
+			if round < len(m[k]) {
+				result = append(result, m[k][round])
+			}
+		}
+	}
+
+	return result
+}
+
+// GroupWithBudget groups the elements of the provided slice in the same way as
+// FromSliceWithDuplicates, except that it stops once maxTotal elements have been
+// grouped, across all keys. The elements beyond the first maxTotal elements of
+// the provided slice are dropped, and the key function is not called for them.
+// If maxTotal is less than one, the map is empty.
+func GroupWithBudget[E any, K comparable](s []E, key func(e E) K, maxTotal int) map[K][]E {
+	if maxTotal < 0 {
+		maxTotal = 0
+	}
+
+	if len(s) > maxTotal {
+		s = s[:maxTotal]
+	}
+
+	return FromSliceWithDuplicates(s, key)
+}
+
+// GroupChecksums creates a map of the keys of the provided map of grouped
+// elements to a checksum of their slice, combined from the hash of each element
+// as returned by the hash function. The checksum does not depend on the order
+// of the elements, so comparing the checksums of two groupings detects whether
+// their slices hold the same elements without comparing the slices. The element
+// hashes are scrambled and summed rather than XORed, so that pairs of identical
+// elements do not cancel each other out.
+func GroupChecksums[K comparable, E any](m map[K][]E, hash func(e E) uint64) map[K]uint64 {
+	checksums := make(map[K]uint64, len(m))
+
+	for k, bucket := range m {
+		var sum uint64
+		for _, e := range bucket {
+			sum += mix64(hash(e))
+		}
+
+		checksums[k] = sum
+	}
+
+	return checksums
+}
+
+// SplitHotKeys creates a map of string keys from the provided map of grouped
+// elements, in which the slices holding more than maxBucket elements are split
+// into consecutive sub-slices of at most maxBucket elements. The sub-slices of a
+// split key are stored under the key formatted with fmt.Sprint and followed by
+// "#0", "#1", and so on, while the other slices are stored under the formatted
+// key alone. This spreads skewed groupings more evenly across parallel
+// consumers. The caller must ensure that the formatted keys cannot collide. The
+// sub-slices share the backing arrays of the original slices but have their
+// capacity limited so that appending to them does not overwrite other
+// elements. If maxBucket is less than one, a limit of one element is used.
+func SplitHotKeys[K comparable, E any](m map[K][]E, maxBucket int) map[string][]E {
+	if maxBucket < 1 {
+		maxBucket = 1
+	}
+
+	split := make(map[string][]E, len(m))
+
+	for k, bucket := range m {
+		name := fmt.Sprint(k)
+
+		if len(bucket) <= maxBucket {
+			split[name] = bucket
+			continue
+		}
+
+		for i := 0; i*maxBucket < len(bucket); i++ {
+			end := min((i+1)*maxBucket, len(bucket))
+
+			split[name+"#"+strconv.Itoa(i)] = bucket[i*maxBucket : end : end]
+		}
+	}
+
+	return split
+}
+
+// GroupStateful groups the elements of the provided slice in the same way as
+// FromSliceWithDuplicates, except that the key of each element is determined
+// by the classify function, which also receives a state threaded through the
+// slice. The state starts with the initial value and is replaced, after each
+// element, by the state returned by classify. This allows keys that depend on
+// the preceding elements, such as grouping consecutive elements until a
+// boundary element is encountered.
+func GroupStateful[E any, K comparable, S any](s []E, initial S, classify func(state S, e E) (K, S)) map[K][]E {
+	m := make(map[K][]E)
+	state := initial
+
+	for _, e := range s {
+		var k K
+		k, state = classify(state, e)
+
+		m[k] = append(m[k], e)
+	}
+
+	return m
+}
+
+// MergeGroupsWithReport merges the two provided maps of grouped elements into a
+// new map, in which the slice of each key holds the elements of a followed by
+// the elements of b. It also returns the keys present in both maps, in an
+// unspecified order, which is useful to audit merges. The provided maps and
+// their slices are not modified.
+func MergeGroupsWithReport[K comparable, E any](a, b map[K][]E) (merged map[K][]E, overlappingKeys []K) {
+	merged = make(map[K][]E, len(a)+len(b))
+	overlappingKeys = make([]K, 0)
+
+	for k, v := range a {
+		merged[k] = slices.Clone(v)
+	}
+
+	for k, v := range b {
+		if _, ok := a[k]; ok {
+			overlappingKeys = append(overlappingKeys, k)
+		}
+
+		merged[k] = append(merged[k], v...)
+	}
+
+	return merged, overlappingKeys
+}
+
+// GroupSortedInput groups the consecutive elements of the provided slice that
+// have the same key, as determined by the key function, without building a map.
+// It assumes that the elements are sorted, or at least clustered, by key, so
+// that all of the elements of a key are contiguous. The groups are returned in
+// the order they appear in the provided slice. If that precondition does not
+// hold, a key whose elements are not contiguous appears in several groups, one
+// per run of consecutive elements.
+func GroupSortedInput[E any, K comparable](s []E, key func(e E) K) []struct {
+	Key   K
+	Items []E
+} {
+	groups := make([]struct {
+		Key   K
+		Items []E
+	}, 0)
+
+	for _, e := range s {
+		k := key(e)
+
+		if n := len(groups); n > 0 && groups[n-1].Key == k {
+			groups[n-1].Items = append(groups[n-1].Items, e)
+			continue
+		}
+
+		groups = append(groups, struct {
+			Key   K
+			Items []E
+		}{Key: k, Items: []E{e}})
+	}
+
+	return groups
+}
@@ -0,0 +1,487 @@
+package mapify
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// TestPageKeys verifies that the PageKeys function can be used to walk through
+// all of the keys of a map of grouped elements, one page at a time, in
+// ascending order.
+func TestPageKeys(t *testing.T) {
+	groups := map[string][]*TestUser{
+		"e": {&testUserBob},
+		"a": {&testUserAlice},
+		"d": {&testUserFred, &testUserMarie},
+		"b": {&testUserBob},
+		"c": {&testUserAlice},
+	}
+
+	for _, tc := range []struct {
+		name     string
+		limit    int
+		expected [][]string
+	}{
+		{
+			name:     "uneven-pages",
+			limit:    2,
+			expected: [][]string{{"a", "b"}, {"c", "d"}, {"e"}},
+		},
+		{
+			name:     "even-pages",
+			limit:    5,
+			expected: [][]string{{"a", "b", "c", "d", "e"}},
+		},
+		{
+			name:     "single-key-pages",
+			limit:    1,
+			expected: [][]string{{"a"}, {"b"}, {"c"}, {"d"}, {"e"}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				cursor string
+				pages  [][]string
+			)
+
+			for {
+				keys, next, hasMore := PageKeys(groups, cursor, tc.limit)
+				pages = append(pages, keys)
+				cursor = next
+
+				if !hasMore {
+					break
+				}
+
+				if len(pages) > len(groups) {
+					t.Fatal("PageKeys did not stop returning pages")
+				}
+			}
+
+			verifyPages(t, tc.expected, pages)
+		})
+	}
+}
+
+// TestPageKeys_Empty verifies that PageKeys returns no keys, the provided
+// cursor and no indication of further pages when the map is empty or when the
+// cursor is past the last key.
+func TestPageKeys_Empty(t *testing.T) {
+	keys, next, hasMore := PageKeys(map[int][]string{}, 0, 10)
+	if len(keys) != 0 || next != 0 || hasMore {
+		t.Fatalf("unexpected page for empty map: %v, %v, %v", keys, next, hasMore)
+	}
+
+	keys, next, hasMore = PageKeys(map[int][]string{1: {"one"}, 2: {"two"}}, 2, 10)
+	if len(keys) != 0 || next != 2 || hasMore {
+		t.Fatalf("unexpected page past the last key: %v, %v, %v", keys, next, hasMore)
+	}
+}
+
+// TestFirstPageKeys verifies that walking through the keys of a map with
+// FirstPageKeys then PageKeys returns every key, including the keys less than or
+// equal to the zero value of K.
+func TestFirstPageKeys(t *testing.T) {
+	ints := map[int][]string{-3: {"a"}, -1: {"b"}, 0: {"c"}, 2: {"d"}, 5: {"e"}}
+
+	keys, next, hasMore := FirstPageKeys(ints, 2)
+	pages := [][]int{keys}
+
+	for hasMore {
+		keys, next, hasMore = PageKeys(ints, next, 2)
+		pages = append(pages, keys)
+
+		if len(pages) > len(ints) {
+			t.Fatal("PageKeys did not stop returning pages")
+		}
+	}
+
+	verifyPages(t, [][]int{{-3, -1}, {0, 2}, {5}}, pages)
+
+	strs := map[string][]int{"": {1}, "a": {2}, "b": {3}}
+
+	keys2, next2, hasMore := FirstPageKeys(strs, 10)
+	if hasMore || next2 != "b" {
+		t.Fatalf("unexpected single page: %v, %q, %v", keys2, next2, hasMore)
+	}
+
+	verifySlice(t, []string{"", "a", "b"}, keys2)
+
+	keys, next, hasMore = FirstPageKeys(map[int][]string{}, 10)
+	if len(keys) != 0 || next != 0 || hasMore {
+		t.Fatalf("unexpected page for empty map: %v, %v, %v", keys, next, hasMore)
+	}
+}
+
+// verifyPages is a convenience function to verify that the pages of keys
+// returned by successive calls to PageKeys match the expected pages.
+func verifyPages[K comparable](t *testing.T, expected, actual [][]K) {
+	if len(actual) != len(expected) {
+		t.Fatalf("expected %d pages but got %d: %v", len(expected), len(actual), actual)
+	}
+
+	for i := range expected {
+		verifySlice(t, expected[i], actual[i])
+	}
+}
+
+// verifySlice is a convenience function to verify that an actual slice has the
+// same elements, in the same order, as an expected slice.
+func verifySlice[E comparable](t *testing.T, expected, actual []E) {
+	if len(actual) != len(expected) {
+		t.Logf("length of actual is expected to be %d but was %d: %v", len(expected), len(actual), actual)
+		t.Fail()
+		return
+	}
+
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Logf("actual is expected to contain %v at index %d but contained %v", expected[i], i, actual[i])
+			t.Fail()
+		}
+	}
+}
+
+// TestShuffleGroups verifies that the ShuffleGroups function produces the same
+// shuffle when given identically seeded random number generators and that the
+// elements of each slice are preserved.
+func TestShuffleGroups(t *testing.T) {
+	newGroups := func() map[string][]int {
+		return map[string][]int{
+			"small": {1, 2, 3},
+			"large": {1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+			"dupes": {7, 7, 8, 8, 9, 9, 9},
+			"empty": {},
+		}
+	}
+
+	first := newGroups()
+	second := newGroups()
+
+	ShuffleGroups(first, rand.New(rand.NewSource(42)))
+	ShuffleGroups(second, rand.New(rand.NewSource(42)))
+
+	for k, v := range first {
+		verifySlice(t, v, second[k])
+	}
+
+	if slices.Equal(first["large"], newGroups()["large"]) {
+		t.Log("actual is expected to be shuffled but was still in the original order")
+		t.Fail()
+	}
+
+	for k, v := range newGroups() {
+		sorted := slices.Clone(first[k])
+		slices.Sort(sorted)
+
+		verifySlice(t, v, sorted)
+	}
+}
+
+// TestShuffleGroups_Pinned verifies that ShuffleGroups produces a known
+// shuffle for string keys and a fixed seed, so that a change to the way keys are
+// hashed or seeds are derived, which would make earlier results unreproducible,
+// does not go unnoticed.
+func TestShuffleGroups_Pinned(t *testing.T) {
+	groups := map[string][]int{
+		"small": {1, 2, 3},
+		"large": {1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		"dupes": {7, 7, 8, 8, 9, 9, 9},
+	}
+
+	ShuffleGroups(groups, rand.New(rand.NewSource(42)))
+
+	verifySlice(t, []int{3, 1, 2}, groups["small"])
+	verifySlice(t, []int{3, 12, 7, 14, 13, 6, 15, 2, 8, 9, 16, 11, 4, 1, 10, 5}, groups["large"])
+	verifySlice(t, []int{7, 9, 9, 9, 8, 7, 8}, groups["dupes"])
+}
+
+// TestSortedGroupsByAggregate verifies that the SortedGroupsByAggregate
+// function returns the keys in descending order of their aggregate, with ties
+// kept in order of first appearance, along with the grouping.
+func TestSortedGroupsByAggregate(t *testing.T) {
+	input := []string{"b1", "a1", "c1", "a2", "c2", "a3", "d1"}
+
+	keys, groups := SortedGroupsByAggregate(input, func(s string) byte {
+		return s[0]
+	}, func(group []string) int {
+		return len(group)
+	})
+
+	verifySlice(t, []byte{'a', 'c', 'b', 'd'}, keys)
+	verifySlice(t, []string{"a1", "a2", "a3"}, groups['a'])
+	verifySlice(t, []string{"b1"}, groups['b'])
+	verifySlice(t, []string{"c1", "c2"}, groups['c'])
+	verifySlice(t, []string{"d1"}, groups['d'])
+}
+
+// TestFilterInGroups verifies that the FilterInGroups function filters each
+// slice of the map in place and deletes the keys whose slice becomes empty.
+func TestFilterInGroups(t *testing.T) {
+	groups := map[string][]int{
+		"mixed": {1, 2, 3, 4, 5, 6},
+		"odd":   {1, 3, 5},
+		"even":  {2, 4},
+		"empty": {},
+	}
+
+	mixed := groups["mixed"]
+
+	FilterInGroups(groups, func(e int) bool {
+		return e%2 == 0
+	})
+
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 keys but got %v", groups)
+	}
+
+	verifySlice(t, []int{2, 4, 6}, groups["mixed"])
+	verifySlice(t, []int{2, 4}, groups["even"])
+	verifySlice(t, []int{2, 4, 6}, mixed[:3])
+}
+
+// TestInterleaveGroups verifies that the InterleaveGroups function takes the
+// elements of each slice in turns, in ascending key order, and appends the tails
+// of the longer slices once the shorter ones are exhausted.
+func TestInterleaveGroups(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		input    map[string][]int
+		expected []int
+	}{
+		{
+			name:     "empty-map",
+			input:    map[string][]int{},
+			expected: []int{},
+		},
+		{
+			name: "uneven-slices",
+			input: map[string][]int{
+				"c": {31},
+				"a": {11, 12, 13, 14},
+				"b": {21, 22},
+				"d": {},
+			},
+			expected: []int{11, 21, 31, 12, 22, 13, 14},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			verifySlice(t, tc.expected, InterleaveGroups(tc.input))
+		})
+	}
+}
+
+// TestGroupWithBudget verifies that the GroupWithBudget function never groups
+// more than the maximum number of elements and drops the later ones.
+func TestGroupWithBudget(t *testing.T) {
+	input := []int{1, 2, 3, 4, 5, 6, 7}
+	key := func(e int) int { return e % 2 }
+
+	for _, tc := range []struct {
+		name     string
+		maxTotal int
+		expected map[int][]int
+	}{
+		{
+			name:     "zero-budget",
+			maxTotal: 0,
+			expected: map[int][]int{},
+		},
+		{
+			name:     "partial-budget",
+			maxTotal: 4,
+			expected: map[int][]int{0: {2, 4}, 1: {1, 3}},
+		},
+		{
+			name:     "budget-exceeding-input",
+			maxTotal: 100,
+			expected: map[int][]int{0: {2, 4, 6}, 1: {1, 3, 5, 7}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result := GroupWithBudget(input, key, tc.maxTotal)
+
+			total := 0
+			for _, v := range result {
+				total += len(v)
+			}
+
+			if total > tc.maxTotal {
+				t.Fatalf("expected at most %d elements but got %d", tc.maxTotal, total)
+			}
+
+			if len(result) != len(tc.expected) {
+				t.Fatalf("expected %d keys but got %v", len(tc.expected), result)
+			}
+
+			for k, v := range tc.expected {
+				verifySlice(t, v, result[k])
+			}
+		})
+	}
+}
+
+// TestGroupChecksums verifies that the checksums computed by the GroupChecksums
+// function do not depend on the order of the elements but change when an
+// element changes.
+func TestGroupChecksums(t *testing.T) {
+	hash := func(u *TestUser) uint64 {
+		return uint64(u.id)
+	}
+
+	original := GroupChecksums(map[string][]*TestUser{
+		"boys":  {&testUserBob, &testUserFred},
+		"girls": {&testUserAlice, &testUserMarie, &testUserMarie},
+	}, hash)
+
+	reordered := GroupChecksums(map[string][]*TestUser{
+		"boys":  {&testUserFred, &testUserBob},
+		"girls": {&testUserMarie, &testUserAlice, &testUserMarie},
+	}, hash)
+
+	verifyResult(t, original, reordered)
+
+	changed := GroupChecksums(map[string][]*TestUser{
+		"boys":  {&testUserBob, &testUserAlice},
+		"girls": {&testUserAlice, &testUserMarie},
+	}, hash)
+
+	if changed["boys"] == original["boys"] {
+		t.Log("checksum of boys is expected to change when an element changes")
+		t.Fail()
+	}
+
+	if changed["girls"] == original["girls"] {
+		t.Log("checksum of girls is expected to change when a duplicate element is removed")
+		t.Fail()
+	}
+}
+
+// TestSplitHotKeys verifies that the SplitHotKeys function splits the slices
+// exceeding the limit into the right number of sub-slices and leaves the other
+// slices under their original key.
+func TestSplitHotKeys(t *testing.T) {
+	groups := map[int][]int{
+		1: {1, 2, 3, 4, 5, 6, 7},
+		2: {8, 9, 10},
+		3: {11},
+	}
+
+	result := SplitHotKeys(groups, 3)
+
+	if len(result) != 5 {
+		t.Fatalf("expected 5 keys but got %v", result)
+	}
+
+	verifySlice(t, []int{1, 2, 3}, result["1#0"])
+	verifySlice(t, []int{4, 5, 6}, result["1#1"])
+	verifySlice(t, []int{7}, result["1#2"])
+	verifySlice(t, []int{8, 9, 10}, result["2"])
+	verifySlice(t, []int{11}, result["3"])
+
+	_ = append(result["1#0"], 100)
+	verifySlice(t, []int{4, 5, 6}, result["1#1"])
+}
+
+// TestGroupStateful verifies that the GroupStateful function threads the state
+// through the classify function, allowing consecutive elements to be grouped
+// until a boundary element.
+func TestGroupStateful(t *testing.T) {
+	input := []string{"a", "b", "|", "c", "|", "|", "d", "e", "f"}
+
+	result := GroupStateful(input, 0, func(group int, e string) (int, int) {
+		if e == "|" {
+			return group + 1, group + 1
+		}
+
+		return group, group
+	})
+
+	if len(result) != 4 {
+		t.Fatalf("expected 4 keys but got %v", result)
+	}
+
+	verifySlice(t, []string{"a", "b"}, result[0])
+	verifySlice(t, []string{"|", "c"}, result[1])
+	verifySlice(t, []string{"|"}, result[2])
+	verifySlice(t, []string{"|", "d", "e", "f"}, result[3])
+}
+
+// TestMergeGroupsWithReport verifies that the MergeGroupsWithReport function
+// concatenates the slices of both maps and reports the overlapping keys.
+func TestMergeGroupsWithReport(t *testing.T) {
+	a := map[string][]int{
+		"x": {1, 2},
+		"y": {3},
+	}
+	b := map[string][]int{
+		"y": {4, 5},
+		"z": {6},
+	}
+
+	merged, overlapping := MergeGroupsWithReport(a, b)
+
+	if len(merged) != 3 {
+		t.Fatalf("expected 3 keys but got %v", merged)
+	}
+
+	verifySlice(t, []int{1, 2}, merged["x"])
+	verifySlice(t, []int{3, 4, 5}, merged["y"])
+	verifySlice(t, []int{6}, merged["z"])
+	verifySlice(t, []string{"y"}, overlapping)
+	verifySlice(t, []int{3}, a["y"])
+
+	_, overlapping = MergeGroupsWithReport(a, map[string][]int{"w": {7}})
+	verifySlice(t, []string{}, overlapping)
+}
+
+// TestGroupSortedInput verifies that the GroupSortedInput function groups the
+// runs of consecutive elements with the same key, and that keys whose elements
+// are not contiguous appear once per run.
+func TestGroupSortedInput(t *testing.T) {
+	key := func(s string) byte {
+		return s[0]
+	}
+
+	for _, tc := range []struct {
+		name          string
+		input         []string
+		expectedKeys  []byte
+		expectedItems [][]string
+	}{
+		{
+			name:          "nil-input-slice",
+			input:         nil,
+			expectedKeys:  []byte{},
+			expectedItems: [][]string{},
+		},
+		{
+			name:          "sorted-input",
+			input:         []string{"apple", "avocado", "banana", "cherry", "citron"},
+			expectedKeys:  []byte{'a', 'b', 'c'},
+			expectedItems: [][]string{{"apple", "avocado"}, {"banana"}, {"cherry", "citron"}},
+		},
+		{
+			name:          "unsorted-input",
+			input:         []string{"apple", "banana", "avocado", "apricot"},
+			expectedKeys:  []byte{'a', 'b', 'a'},
+			expectedItems: [][]string{{"apple"}, {"banana"}, {"avocado", "apricot"}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				keys  = []byte{}
+				items = [][]string{}
+			)
+
+			for _, g := range GroupSortedInput(tc.input, key) {
+				keys = append(keys, g.Key)
+				items = append(items, g.Items)
+			}
+
+			verifySlice(t, tc.expectedKeys, keys)
+			verifyPages(t, tc.expectedItems, items)
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package mapify
+
+import (
+	"cmp"
+	"slices"
+	"sort"
+)
+
+// ImmutableGroups is a read-only view of a map of keys K to slices of E
+// elements, such as one created by FromSliceWithDuplicates. The slices returned
+// by its methods are copies, so callers are free to modify them without
+// affecting the wrapped data. The zero value is an empty view.
+type ImmutableGroups[K comparable, E any] struct {
+	m map[K][]E
+}
+
+// FreezeGroups creates an ImmutableGroups view of the provided map. The map and
+// its slices are copied, so later changes made to the provided map are not
+// reflected in the view.
+func FreezeGroups[K comparable, E any](m map[K][]E) ImmutableGroups[K, E] {
+	frozen := make(map[K][]E, len(m))
+
+	for k, v := range m {
+		frozen[k] = slices.Clone(v)
+	}
+
+	return ImmutableGroups[K, E]{m: frozen}
+}
+
+// Get returns a copy of the slice of elements stored for the provided key and
+// whether the key is present in the view.
+func (g ImmutableGroups[K, E]) Get(k K) ([]E, bool) {
+	v, ok := g.m[k]
+	if !ok {
+		return nil, false
+	}
+
+	return slices.Clone(v), true
+}
+
+// Keys returns the keys of the view in an unspecified order.
+func (g ImmutableGroups[K, E]) Keys() []K {
+	return Keys(g.m)
+}
+
+// Len returns the number of keys in the view.
+func (g ImmutableGroups[K, E]) Len() int {
+	return len(g.m)
+}
+
+// Range calls the function f with each key of the view and a copy of its slice
+// of elements, in an unspecified order. If f returns false, Range stops the
+// iteration.
+func (g ImmutableGroups[K, E]) Range(f func(k K, v []E) bool) {
+	for k, v := range g.m {
+		if !f(k, slices.Clone(v)) {
+			return
+		}
+	}
+}
+
+// ImmutableSortedIndex is a read-only index of elements sorted by an ordered
+// key. It answers point and range queries with a binary search and, since it
+// only stores the sorted keys and elements, takes less space than a map when
+// lookups are mostly range based. It has no method to modify its content and
+// the slices returned by its methods are copies. The zero value, like a nil
+// *ImmutableSortedIndex, is an empty index.
+type ImmutableSortedIndex[K cmp.Ordered, E any] struct {
+	idx *SortedIndex[K, E]
+}
+
+// FreezeSorted creates an ImmutableSortedIndex of the elements of the provided
+// slice, using the key function to determine the key of each element. Elements
+// with equal keys are all kept, in the order they appear in the provided slice.
+func FreezeSorted[E any, K cmp.Ordered](s []E, key func(e E) K) *ImmutableSortedIndex[K, E] {
+	return &ImmutableSortedIndex[K, E]{idx: NewSortedIndex(s, key)}
+}
+
+// Get returns the element stored for the provided key and whether the key is
+// present in the index. When several elements share the key, the first of them
+// in the provided slice is returned.
+func (x *ImmutableSortedIndex[K, E]) Get(k K) (E, bool) {
+	idx := x.sorted()
+	keys := idx.keys
+
+	i := sort.Search(len(keys), func(i int) bool { return keys[i] >= k })
+	if i == len(keys) || keys[i] != k {
+		var zero E
+		return zero, false
+	}
+
+	return idx.elements[i], true
+}
+
+// Range returns the elements whose key is between lo and hi, inclusively,
+// sorted by key.
+func (x *ImmutableSortedIndex[K, E]) Range(lo, hi K) []E {
+	return x.sorted().Range(lo, hi)
+}
+
+// First returns the element with the smallest key and whether the index holds
+// any element.
+func (x *ImmutableSortedIndex[K, E]) First() (E, bool) {
+	idx := x.sorted()
+	if idx.Len() == 0 {
+		var zero E
+		return zero, false
+	}
+
+	return idx.elements[0], true
+}
+
+// Last returns the element with the largest key and whether the index holds any
+// element. When several elements share the largest key, the last of them in the
+// provided slice is returned.
+func (x *ImmutableSortedIndex[K, E]) Last() (E, bool) {
+	idx := x.sorted()
+
+	n := idx.Len()
+	if n == 0 {
+		var zero E
+		return zero, false
+	}
+
+	return idx.elements[n-1], true
+}
+
+// Len returns the number of elements in the index.
+func (x *ImmutableSortedIndex[K, E]) Len() int {
+	return x.sorted().Len()
+}
+
+// sorted returns the wrapped SortedIndex, or an empty one for the zero value
+// and a nil receiver.
+func (x *ImmutableSortedIndex[K, E]) sorted() *SortedIndex[K, E] {
+	if x == nil || x.idx == nil {
+		return &SortedIndex[K, E]{}
+	}
+
+	return x.idx
+}
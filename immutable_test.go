@@ -0,0 +1,166 @@
+package mapify
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// TestFreezeGroups verifies that the ImmutableGroups view created by the
+// FreezeGroups function exposes the grouped elements and that modifying the
+// provided map or the slices returned by the view does not affect its data.
+func TestFreezeGroups(t *testing.T) {
+	groups := map[string][]*TestUser{
+		"boys":  {&testUserBob, &testUserFred},
+		"girls": {&testUserAlice, &testUserMarie},
+	}
+
+	frozen := FreezeGroups(groups)
+
+	groups["boys"][0] = &testUserMarie
+	delete(groups, "girls")
+
+	if frozen.Len() != 2 {
+		t.Fatalf("expected 2 keys but got %d", frozen.Len())
+	}
+
+	boys, ok := frozen.Get("boys")
+	if !ok {
+		t.Fatal("expected the key boys to be present")
+	}
+
+	verifySlice(t, []*TestUser{&testUserBob, &testUserFred}, boys)
+
+	boys[0] = &testUserAlice
+	boys = append(boys, &testUserMarie)
+
+	boys, _ = frozen.Get("boys")
+	verifySlice(t, []*TestUser{&testUserBob, &testUserFred}, boys)
+
+	if _, ok := frozen.Get("others"); ok {
+		t.Log("expected the key others to be absent")
+		t.Fail()
+	}
+
+	keys := frozen.Keys()
+	if len(keys) != 2 {
+		t.Logf("expected 2 keys but got %v", keys)
+		t.Fail()
+	}
+
+	visited := 0
+	frozen.Range(func(k string, v []*TestUser) bool {
+		visited++
+		v[0] = nil
+
+		return false
+	})
+
+	if visited != 1 {
+		t.Logf("expected Range to stop after the first key but visited %d", visited)
+		t.Fail()
+	}
+
+	girls, _ := frozen.Get("girls")
+	verifySlice(t, []*TestUser{&testUserAlice, &testUserMarie}, girls)
+
+	boys, _ = frozen.Get("boys")
+	verifySlice(t, []*TestUser{&testUserBob, &testUserFred}, boys)
+}
+
+// TestFreezeSorted verifies that the ImmutableSortedIndex created by the
+// FreezeSorted function answers point and range queries, and that it exposes no
+// method that could modify its content.
+func TestFreezeSorted(t *testing.T) {
+	input := []*TestUser{&testUserMarie, &testUserBob, &testUserFred, &testUserAlice}
+
+	idx := FreezeSorted(input, func(u *TestUser) int { return u.id })
+
+	if idx.Len() != 4 {
+		t.Fatalf("expected 4 elements but got %d", idx.Len())
+	}
+
+	for _, u := range input {
+		if e, ok := idx.Get(u.id); !ok || e != u {
+			t.Logf("expected key %d to hold %v but got %v, %t", u.id, u, e, ok)
+			t.Fail()
+		}
+	}
+
+	for _, k := range []int{0, 5} {
+		if e, ok := idx.Get(k); ok {
+			t.Logf("expected key %d to be absent but got %v", k, e)
+			t.Fail()
+		}
+	}
+
+	r := idx.Range(2, 3)
+	verifySlice(t, []*TestUser{&testUserAlice, &testUserFred}, r)
+
+	r[0] = nil
+	verifySlice(t, []*TestUser{&testUserAlice, &testUserFred}, idx.Range(2, 3))
+	verifySlice(t, []*TestUser{}, idx.Range(5, 10))
+
+	if e, ok := idx.First(); !ok || e != &testUserBob {
+		t.Logf("expected the first element to be %v but got %v, %t", &testUserBob, e, ok)
+		t.Fail()
+	}
+
+	if e, ok := idx.Last(); !ok || e != &testUserMarie {
+		t.Logf("expected the last element to be %v but got %v, %t", &testUserMarie, e, ok)
+		t.Fail()
+	}
+
+	empty := FreezeSorted([]*TestUser{}, func(u *TestUser) int { return u.id })
+	if _, ok := empty.First(); ok {
+		t.Log("expected no first element in an empty index")
+		t.Fail()
+	}
+
+	if _, ok := empty.Last(); ok {
+		t.Log("expected no last element in an empty index")
+		t.Fail()
+	}
+
+	var methods []string
+	typ := reflect.TypeOf(idx)
+	for i := 0; i < typ.NumMethod(); i++ {
+		methods = append(methods, typ.Method(i).Name)
+	}
+
+	slices.Sort(methods)
+	verifySlice(t, []string{"First", "Get", "Last", "Len", "Range"}, methods)
+}
+
+// TestImmutableSortedIndex_ZeroValue verifies that the zero value of
+// ImmutableSortedIndex, and a nil *ImmutableSortedIndex, behave as an empty
+// index rather than panicking.
+func TestImmutableSortedIndex_ZeroValue(t *testing.T) {
+	var zero ImmutableSortedIndex[int, *TestUser]
+	var nilIndex *ImmutableSortedIndex[int, *TestUser]
+
+	for name, idx := range map[string]*ImmutableSortedIndex[int, *TestUser]{
+		"zero-value": &zero,
+		"nil":        nilIndex,
+	} {
+		t.Run(name, func(t *testing.T) {
+			if idx.Len() != 0 {
+				t.Fatalf("expected no elements but got %d", idx.Len())
+			}
+
+			if e, ok := idx.Get(1); ok {
+				t.Fatalf("expected key 1 to be absent but got %v", e)
+			}
+
+			if _, ok := idx.First(); ok {
+				t.Fatal("expected no first element")
+			}
+
+			if _, ok := idx.Last(); ok {
+				t.Fatal("expected no last element")
+			}
+
+			verifySlice(t, []*TestUser{}, idx.Range(0, 10))
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package mapify
+
+import (
+	"cmp"
+	"slices"
+	"sort"
+)
+
+// SortedIndex holds elements sorted by an ordered key, which allows efficient
+// range queries that maps cannot provide.
+type SortedIndex[K cmp.Ordered, E any] struct {
+	keys     []K
+	elements []E
+}
+
+// NewSortedIndex creates a SortedIndex of the elements of the provided slice,
+// using the key function to determine the key of each element. Elements with
+// equal keys are all kept, in the order they appear in the provided slice.
+func NewSortedIndex[E any, K cmp.Ordered](s []E, key func(e E) K) *SortedIndex[K, E] {
+	order := indexRange(len(s))
+	keys := make([]K, len(s))
+
+	for i, e := range s {
+		keys[i] = key(e)
+	}
+
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(keys[a], keys[b])
+	})
+
+	idx := &SortedIndex[K, E]{
+		keys:     make([]K, len(s)),
+		elements: make([]E, len(s)),
+	}
+
+	for i, j := range order {
+		idx.keys[i] = keys[j]
+		idx.elements[i] = s[j]
+	}
+
+	return idx
+}
+
+// Range returns the elements whose key is between lo and hi, inclusively,
+// sorted by key. It uses a binary search to locate the range.
+func (idx *SortedIndex[K, E]) Range(lo, hi K) []E {
+	start := sort.Search(len(idx.keys), func(i int) bool { return idx.keys[i] >= lo })
+	end := sort.Search(len(idx.keys), func(i int) bool { return idx.keys[i] > hi })
+
+	if start >= end {
+		return []E{}
+	}
+
+	return slices.Clone(idx.elements[start:end])
+}
+
+// Len returns the number of elements in the index.
+func (idx *SortedIndex[K, E]) Len() int {
+	return len(idx.elements)
+}
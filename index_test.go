@@ -0,0 +1,62 @@
+package mapify
+
+import (
+	"testing"
+)
+
+// TestSortedIndex_Range verifies that the Range method of a SortedIndex returns
+// the elements whose key falls within the inclusive range, sorted by key.
+func TestSortedIndex_Range(t *testing.T) {
+	input := []*TestUser{&testUserMarie, &testUserBob, &testUserFred, &testUserAlice}
+	testUserAlanna := TestUser{id: 2, name: "alanna"}
+	input = append(input, &testUserAlanna)
+
+	idx := NewSortedIndex(input, func(u *TestUser) int {
+		return u.id
+	})
+
+	if idx.Len() != 5 {
+		t.Fatalf("expected 5 elements but got %d", idx.Len())
+	}
+
+	for _, tc := range []struct {
+		name     string
+		lo, hi   int
+		expected []*TestUser
+	}{
+		{
+			name:     "inner-range",
+			lo:       2,
+			hi:       3,
+			expected: []*TestUser{&testUserAlice, &testUserAlanna, &testUserFred},
+		},
+		{
+			name:     "whole-range",
+			lo:       0,
+			hi:       10,
+			expected: []*TestUser{&testUserBob, &testUserAlice, &testUserAlanna, &testUserFred, &testUserMarie},
+		},
+		{
+			name:     "single-key",
+			lo:       4,
+			hi:       4,
+			expected: []*TestUser{&testUserMarie},
+		},
+		{
+			name:     "empty-range",
+			lo:       5,
+			hi:       10,
+			expected: []*TestUser{},
+		},
+		{
+			name:     "inverted-range",
+			lo:       3,
+			hi:       2,
+			expected: []*TestUser{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			verifySlice(t, tc.expected, idx.Range(tc.lo, tc.hi))
+		})
+	}
+}
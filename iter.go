@@ -0,0 +1,116 @@
+package mapify
+
+import (
+	"cmp"
+	"iter"
+	"maps"
+)
+
+// FromSeq creates a map in the same way as FromSlice, using the elements
+// yielded by the provided sequence instead of the elements of a slice, so that
+// streaming sources do not need to be collected into a slice first. A nil
+// sequence results in an empty map.
+func FromSeq[E any, K comparable](seq iter.Seq[E], key func(e E) K) map[K]E {
+	m := make(map[K]E)
+
+	if seq == nil {
+		return m
+	}
+
+	for e := range seq {
+		m[key(e)] = e
+	}
+
+	return m
+}
+
+// GroupSeqSorted groups the elements of the provided slice in the same way as
+// FromSliceWithDuplicates and returns a sequence that yields each key with its
+// slice of elements, in ascending key order. The grouping is done each time the
+// sequence is iterated, and iterating stops as soon as the consumer stops, for
+// example by breaking out of a range loop.
+func GroupSeqSorted[E any, K cmp.Ordered](s []E, key func(e E) K) iter.Seq2[K, []E] {
+	return func(yield func(K, []E) bool) {
+		m := FromSliceWithDuplicates(s, key)
+
+		for _, k := range SortedKeys(m) {
+			if !yield(k, m[k]) {
+				return
+			}
+		}
+	}
+}
+
+// Entries returns a sequence that yields each key and value of the provided
+// map, in an unspecified order. The entries are copied when Entries is called,
+// so the sequence can be iterated any number of times and always yields that
+// same snapshot, even if the map is modified afterwards.
+func Entries[K comparable, V any](m map[K]V) iter.Seq2[K, V] {
+	snapshot := maps.Clone(m)
+
+	return func(yield func(K, V) bool) {
+		for k, v := range snapshot {
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// The labels yielded by the sequence returned by DiffSorted.
+const (
+	DiffAdded   = "added"
+	DiffRemoved = "removed"
+)
+
+// DiffSorted returns a sequence that yields the differences between the old
+// and new slices, without building any map. Each element of new whose key is
+// not present in old is yielded with the DiffAdded label, and each element of
+// old whose key is not present in new is yielded with the DiffRemoved label.
+// Elements are yielded in ascending key order, which makes DiffSorted suitable
+// for very large inputs since only one element of each slice is considered at a
+// time.
+//
+// Both slices must already be sorted in ascending order of the key returned by
+// the key function, for example with slices.SortFunc; DiffSorted does not check
+// this and yields meaningless differences otherwise. When a key appears several
+// times, the occurrences of old and new are paired one for one and only the
+// unpaired ones are yielded. Elements are compared by key only, so an element
+// whose key is present in both slices is never yielded, even if it changed.
+func DiffSorted[E any, K cmp.Ordered](old, new []E, key func(e E) K) iter.Seq2[string, E] {
+	return func(yield func(string, E) bool) {
+		i, j := 0, 0
+
+		for i < len(old) && j < len(new) {
+			switch c := cmp.Compare(key(old[i]), key(new[j])); {
+			case c < 0:
+				if !yield(DiffRemoved, old[i]) {
+					return
+				}
+
+				i++
+			case c > 0:
+				if !yield(DiffAdded, new[j]) {
+					return
+				}
+
+				j++
+			default:
+				i++
+				j++
+			}
+		}
+
+		for ; i < len(old); i++ {
+			if !yield(DiffRemoved, old[i]) {
+				return
+			}
+		}
+
+		for ; j < len(new); j++ {
+			if !yield(DiffAdded, new[j]) {
+				return
+			}
+		}
+	}
+}
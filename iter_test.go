@@ -0,0 +1,160 @@
+package mapify
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+// TestFromSeq verifies that the FromSeq function builds the same map as
+// FromSlice from the elements of a sequence.
+func TestFromSeq(t *testing.T) {
+	testUserBobby := TestUser{id: 1, name: "bobby"}
+	input := []*TestUser{&testUserBob, &testUserAlice, &testUserBobby}
+
+	key := func(u *TestUser) int {
+		return u.id
+	}
+
+	verifyResult(t, FromSlice(input, key), FromSeq(slices.Values(input), key))
+	verifyResult(t, map[int]*TestUser{1: &testUserBobby, 2: &testUserAlice}, FromSeq(slices.Values(input), key))
+	verifyResult(t, map[int]*TestUser{}, FromSeq(nil, key))
+}
+
+// TestGroupSeqSorted verifies that the sequence returned by GroupSeqSorted
+// yields the groups in ascending key order and stops when the consumer stops.
+func TestGroupSeqSorted(t *testing.T) {
+	input := []string{"cherry", "apple", "banana", "avocado", "blueberry"}
+	key := func(s string) byte {
+		return s[0]
+	}
+
+	var (
+		keys   []byte
+		groups [][]string
+	)
+
+	for k, group := range GroupSeqSorted(input, key) {
+		keys = append(keys, k)
+		groups = append(groups, group)
+	}
+
+	verifySlice(t, []byte{'a', 'b', 'c'}, keys)
+	verifyPages(t, [][]string{{"apple", "avocado"}, {"banana", "blueberry"}, {"cherry"}}, groups)
+
+	keys = nil
+	for k := range GroupSeqSorted(input, key) {
+		keys = append(keys, k)
+
+		if k == 'b' {
+			break
+		}
+	}
+
+	verifySlice(t, []byte{'a', 'b'}, keys)
+}
+
+// TestEntries verifies that the sequence returned by Entries yields every entry
+// of the map, can be iterated several times and is not affected by later
+// modifications of the map.
+func TestEntries(t *testing.T) {
+	m := map[string]int{"bob": 1, "alice": 2, "fred": 3}
+	entries := Entries(m)
+
+	m["marie"] = 4
+	delete(m, "bob")
+
+	expected := map[string]int{"bob": 1, "alice": 2, "fred": 3}
+
+	verifyResult(t, expected, maps.Collect(entries))
+	verifyResult(t, expected, maps.Collect(entries))
+
+	n := 0
+	for range entries {
+		n++
+		break
+	}
+
+	if n != 1 {
+		t.Fatalf("expected iteration to stop after 1 entry but got %d", n)
+	}
+
+	verifyResult(t, map[string]int{}, maps.Collect(Entries[string, int](nil)))
+}
+
+// TestDiffSorted verifies that the sequence returned by DiffSorted yields the
+// removed and added elements in key order, pairs repeated keys and stops when
+// the consumer stops.
+func TestDiffSorted(t *testing.T) {
+	type change struct {
+		label string
+		e     int
+	}
+
+	key := func(e int) int { return e }
+
+	for _, tc := range []struct {
+		name     string
+		old      []int
+		new      []int
+		expected []change
+	}{
+		{
+			name:     "both-empty",
+			expected: []change{},
+		},
+		{
+			name:     "only-added",
+			new:      []int{1, 2},
+			expected: []change{{DiffAdded, 1}, {DiffAdded, 2}},
+		},
+		{
+			name:     "only-removed",
+			old:      []int{1, 2},
+			expected: []change{{DiffRemoved, 1}, {DiffRemoved, 2}},
+		},
+		{
+			name: "interleaved",
+			old:  []int{1, 3, 4, 6, 9},
+			new:  []int{2, 3, 5, 6, 7, 8},
+			expected: []change{
+				{DiffRemoved, 1},
+				{DiffAdded, 2},
+				{DiffRemoved, 4},
+				{DiffAdded, 5},
+				{DiffAdded, 7},
+				{DiffAdded, 8},
+				{DiffRemoved, 9},
+			},
+		},
+		{
+			name:     "repeated-keys",
+			old:      []int{1, 1, 2},
+			new:      []int{1, 2, 2, 2},
+			expected: []change{{DiffRemoved, 1}, {DiffAdded, 2}, {DiffAdded, 2}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result := make([]change, 0)
+			for label, e := range DiffSorted(tc.old, tc.new, key) {
+				result = append(result, change{label, e})
+			}
+
+			verifySlice(t, tc.expected, result)
+		})
+	}
+
+	count := 0
+	for range DiffSorted([]int{1, 2, 3}, []int{4, 5}, key) {
+		count++
+
+		if count == 2 {
+			break
+		}
+	}
+
+	if count != 2 {
+		t.Logf("expected iteration to stop after 2 changes but got %d", count)
+		t.Fail()
+	}
+}
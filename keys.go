@@ -0,0 +1,25 @@
+package mapify
+
+// CompositeKey combines the two provided key functions into a single key
+// function returning a comparable struct holding both parts of the key. It
+// saves declaring a dedicated struct type when a map must be keyed by more than
+// one value, for example:
+//
+//	m := mapify.FromSlice(users, mapify.CompositeKey(User.TeamID, User.Role))
+//
+// Two elements produce the same key only if both key functions return equal
+// values for them.
+func CompositeKey[E any, A, B comparable](ka func(e E) A, kb func(e E) B) func(e E) struct {
+	A A
+	B B
+} {
+	return func(e E) struct {
+		A A
+		B B
+	} {
+		return struct {
+			A A
+			B B
+		}{A: ka(e), B: kb(e)}
+	}
+}
@@ -0,0 +1,47 @@
+package mapify
+
+import (
+	"testing"
+)
+
+// TestCompositeKey verifies that the key function returned by CompositeKey
+// makes elements collide only when both parts of their key are equal.
+func TestCompositeKey(t *testing.T) {
+	type Member struct {
+		team string
+		role string
+		name string
+	}
+
+	bob := Member{team: "red", role: "dev", name: "bob"}
+	alice := Member{team: "red", role: "ops", name: "alice"}
+	fred := Member{team: "blue", role: "dev", name: "fred"}
+	marie := Member{team: "red", role: "dev", name: "marie"}
+
+	key := CompositeKey(func(m Member) string {
+		return m.team
+	}, func(m Member) string {
+		return m.role
+	})
+
+	if key(bob) != key(marie) {
+		t.Log("elements agreeing on both parts of the key are expected to collide")
+		t.Fail()
+	}
+
+	if key(bob) == key(alice) || key(bob) == key(fred) {
+		t.Log("elements differing on one part of the key are not expected to collide")
+		t.Fail()
+	}
+
+	result := FromSliceWithDuplicates([]Member{bob, alice, fred, marie}, key)
+
+	if len(result) != 3 {
+		t.Fatalf("expected 3 keys but got %d", len(result))
+	}
+
+	verifySlice(t, []Member{bob, marie}, result[struct {
+		A string
+		B string
+	}{A: "red", B: "dev"}])
+}
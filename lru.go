@@ -0,0 +1,101 @@
+package mapify
+
+import (
+	"container/list"
+)
+
+// LRUCache is a map of keys K to values V holding at most a fixed number of
+// entries. When an entry is added to a full cache, the least recently used
+// entry is evicted. Both adding and getting an entry mark it as the most
+// recently used. An LRUCache is not safe for concurrent use.
+type LRUCache[K comparable, V any] struct {
+	capacity int
+	entries  map[K]*list.Element
+	order    *list.List
+}
+
+// lruEntry is the value stored in the elements of the order list of an
+// LRUCache.
+type lruEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// NewLRUCache creates an empty LRUCache that holds at most capacity entries. If
+// capacity is less than one, the cache holds a single entry.
+func NewLRUCache[K comparable, V any](capacity int) *LRUCache[K, V] {
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	return &LRUCache[K, V]{
+		capacity: capacity,
+		entries:  make(map[K]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// FromSliceLRU creates an LRUCache holding at most capacity entries and puts
+// each element of the provided slice in it, in order, using the key function to
+// determine its key. If there are more distinct keys than capacity, only the
+// entries of the last ones put remain in the cache.
+func FromSliceLRU[E any, K comparable](s []E, key func(e E) K, capacity int) *LRUCache[K, E] {
+	c := NewLRUCache[K, E](capacity)
+
+	for _, e := range s {
+		c.Put(key(e), e)
+	}
+
+	return c
+}
+
+// Get returns the value stored for the provided key and whether the key is
+// present in the cache. A present key becomes the most recently used.
+func (c *LRUCache[K, V]) Get(k K) (V, bool) {
+	el, ok := c.entries[k]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+
+	c.order.MoveToFront(el)
+
+	return el.Value.(*lruEntry[K, V]).value, true
+}
+
+// Put stores the value v for the provided key, replacing any value already
+// stored for it, and makes the key the most recently used. If this adds an
+// entry to a full cache, the least recently used entry is evicted.
+func (c *LRUCache[K, V]) Put(k K, v V) {
+	if el, ok := c.entries[k]; ok {
+		el.Value.(*lruEntry[K, V]).value = v
+		c.order.MoveToFront(el)
+
+		return
+	}
+
+	c.entries[k] = c.order.PushFront(&lruEntry[K, V]{key: k, value: v})
+
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry[K, V]).key)
+	}
+}
+
+// Len returns the number of entries in the cache.
+func (c *LRUCache[K, V]) Len() int {
+	return c.order.Len()
+}
+
+// Keys returns the keys of the cache ordered from the most to the least
+// recently used.
+func (c *LRUCache[K, V]) Keys() []K {
+	keys := make([]K, 0, c.order.Len())
+
+	for el := c.order.Front(); el != nil; el = el.Next() {
+		keys = append(keys, el.Value.(*lruEntry[K, V]).key)
+	}
+
+	return keys
+}
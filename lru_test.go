@@ -0,0 +1,73 @@
+package mapify
+
+import (
+	"testing"
+)
+
+// TestFromSliceLRU verifies that the LRUCache created by the FromSliceLRU
+// function evicts the least recently used entries beyond its capacity and that
+// getting an entry makes it the most recently used.
+func TestFromSliceLRU(t *testing.T) {
+	input := []*TestUser{&testUserBob, &testUserAlice, &testUserFred, &testUserMarie}
+
+	cache := FromSliceLRU(input, (*TestUser).ID, 3)
+
+	if cache.Len() != 3 {
+		t.Fatalf("expected 3 entries but got %d", cache.Len())
+	}
+
+	if _, ok := cache.Get("user-1"); ok {
+		t.Log("expected user-1 to have been evicted")
+		t.Fail()
+	}
+
+	verifySlice(t, []string{"user-4", "user-3", "user-2"}, cache.Keys())
+
+	if u, ok := cache.Get("user-2"); !ok || u != &testUserAlice {
+		t.Fatalf("expected user-2 to be %v but got %v, %v", &testUserAlice, u, ok)
+	}
+
+	verifySlice(t, []string{"user-2", "user-4", "user-3"}, cache.Keys())
+
+	cache.Put("user-1", &testUserBob)
+
+	if _, ok := cache.Get("user-3"); ok {
+		t.Log("expected user-3 to have been evicted")
+		t.Fail()
+	}
+
+	verifySlice(t, []string{"user-1", "user-2", "user-4"}, cache.Keys())
+}
+
+// TestFromSliceLRU_RepeatedKey verifies that putting a key already in the
+// cache, as happens when the slice given to FromSliceLRU has duplicate keys,
+// replaces its value and makes it the most recently used without adding an
+// entry or evicting another one.
+func TestFromSliceLRU_RepeatedKey(t *testing.T) {
+	testUserBobby := TestUser{id: 1, name: "bobby"}
+	input := []*TestUser{&testUserBob, &testUserAlice, &testUserFred, &testUserBobby}
+
+	cache := FromSliceLRU(input, (*TestUser).ID, 3)
+
+	if cache.Len() != 3 {
+		t.Fatalf("expected 3 entries but got %d", cache.Len())
+	}
+
+	verifySlice(t, []string{"user-1", "user-3", "user-2"}, cache.Keys())
+
+	if u, ok := cache.Get("user-1"); !ok || u != &testUserBobby {
+		t.Fatalf("expected user-1 to be %v but got %v, %v", &testUserBobby, u, ok)
+	}
+
+	cache.Put("user-2", &testUserMarie)
+
+	if cache.Len() != 3 {
+		t.Fatalf("expected 3 entries after replacing a value but got %d", cache.Len())
+	}
+
+	verifySlice(t, []string{"user-2", "user-1", "user-3"}, cache.Keys())
+
+	if u, ok := cache.Get("user-2"); !ok || u != &testUserMarie {
+		t.Fatalf("expected user-2 to be %v but got %v, %v", &testUserMarie, u, ok)
+	}
+}
@@ -0,0 +1,127 @@
+package mapify
+
+import (
+	"sync"
+)
+
+// MapReduce applies the map-reduce pattern to the provided slice of E
+// elements. The mapper function maps each element to a key and a value, the
+// values are grouped by key in the order of the elements, and the reducer
+// function reduces each key's values to a single result.
+func MapReduce[E any, K comparable, V any, R any](s []E, mapper func(e E) (K, V), reducer func(k K, values []V) R) map[K]R {
+	groups := make(map[K][]V)
+
+	for _, e := range s {
+		k, v := mapper(e)
+
+		groups[k] = append(groups[k], v)
+	}
+
+	return reduceGroups(groups, reducer)
+}
+
+// FlatMapReduce applies the map-reduce pattern to the provided slice of E
+// elements in the same way as MapReduce, except that the mapper function can
+// emit any number of key and value pairs for each element, such as one pair per
+// word of a document.
+func FlatMapReduce[E any, K comparable, V any, R any](s []E, mapper func(e E) []struct {
+	Key   K
+	Value V
+}, reducer func(k K, values []V) R) map[K]R {
+	return reduceGroups(FlatMap(s, mapper), reducer)
+}
+
+// MapReduceParallel applies the map-reduce pattern to the provided slice of E
+// elements in the same way as MapReduce, except that both the mapping and the
+// reducing are spread across the provided number of workers. The slice is split
+// into contiguous chunks that are mapped concurrently into separate groupings,
+// which are then merged and reduced concurrently, each worker reducing a share
+// of the keys. No guarantee is made about the order of the values passed to the
+// reducer, so the reducer should not depend on it. If workers is less than one,
+// a single worker is used.
+func MapReduceParallel[E any, K comparable, V any, R any](s []E, mapper func(e E) (K, V), reducer func(k K, values []V) R, workers int) map[K]R {
+	if workers < 1 {
+		workers = 1
+	}
+
+	chunks := splitChunks(s, workers)
+	shards := make([]map[K][]V, len(chunks))
+
+	var wg sync.WaitGroup
+
+	for i, chunk := range chunks {
+		wg.Add(1)
+
+		go func(i int, chunk []E) {
+			defer wg.Done()
+
+			shard := make(map[K][]V)
+
+			for _, e := range chunk {
+				k, v := mapper(e)
+
+				shard[k] = append(shard[k], v)
+			}
+
+			shards[i] = shard
+		}(i, chunk)
+	}
+
+	wg.Wait()
+
+	groups := make(map[K][]V)
+	for _, shard := range shards {
+		for k, values := range shard {
+			groups[k] = append(groups[k], values...)
+		}
+	}
+
+	keys := make([]K, 0, len(groups))
+	for k := range groups {
+		keys = append(keys, k)
+	}
+
+	results := make([]R, len(keys))
+
+	for _, indices := range splitChunks(indexRange(len(keys)), workers) {
+		wg.Add(1)
+
+		go func(indices []int) {
+			defer wg.Done()
+
+			for _, i := range indices {
+				results[i] = reducer(keys[i], groups[keys[i]])
+			}
+		}(indices)
+	}
+
+	wg.Wait()
+
+	m := make(map[K]R, len(keys))
+	for i, k := range keys {
+		m[k] = results[i]
+	}
+
+	return m
+}
+
+// indexRange returns a slice holding the integers from 0 to n-1, in order.
+func indexRange(n int) []int {
+	indices := make([]int, n)
+	for i := range indices {
+		indices[i] = i
+	}
+
+	return indices
+}
+
+// reduceGroups applies the reducer function to each key's slice of values.
+func reduceGroups[K comparable, V any, R any](groups map[K][]V, reducer func(k K, values []V) R) map[K]R {
+	m := make(map[K]R, len(groups))
+
+	for k, values := range groups {
+		m[k] = reducer(k, values)
+	}
+
+	return m
+}
@@ -0,0 +1,94 @@
+package mapify
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestMapReduce verifies that the MapReduce function groups the values emitted
+// by the mapper by key and reduces each group.
+func TestMapReduce(t *testing.T) {
+	input := []string{"the", "quick", "fox", "jumps", "over", "the", "lazy", "fox", "the"}
+
+	result := MapReduce(input, func(word string) (string, int) {
+		return strings.ToUpper(word), 1
+	}, sumReducer[string])
+
+	verifyResult(t, map[string]int{
+		"THE":   3,
+		"QUICK": 1,
+		"FOX":   2,
+		"JUMPS": 1,
+		"OVER":  1,
+		"LAZY":  1,
+	}, result)
+}
+
+// TestFlatMapReduce verifies that the FlatMapReduce function groups every pair
+// emitted by the mapper by key and reduces each group.
+func TestFlatMapReduce(t *testing.T) {
+	type pair = struct {
+		Key   string
+		Value int
+	}
+
+	input := []string{
+		"the quick fox",
+		"",
+		"the lazy dog and the fox",
+	}
+
+	result := FlatMapReduce(input, func(doc string) []pair {
+		var pairs []pair
+		for _, word := range strings.Fields(doc) {
+			pairs = append(pairs, pair{Key: word, Value: 1})
+		}
+
+		return pairs
+	}, sumReducer[string])
+
+	verifyResult(t, map[string]int{
+		"the":   3,
+		"quick": 1,
+		"fox":   2,
+		"lazy":  1,
+		"dog":   1,
+		"and":   1,
+	}, result)
+}
+
+// TestMapReduceParallel verifies that the MapReduceParallel function produces
+// the same result as MapReduce for a reducer that does not depend on the order
+// of the values.
+func TestMapReduceParallel(t *testing.T) {
+	input := make([]int, 10000)
+	for i := range input {
+		input[i] = i
+	}
+
+	mapper := func(e int) (int, int) {
+		return e % 13, e
+	}
+
+	expected := MapReduce(input, mapper, sumReducer[int])
+
+	for _, workers := range []int{0, 1, 3, 8, 20000} {
+		t.Run(strconv.Itoa(workers)+"-workers", func(t *testing.T) {
+			result := MapReduceParallel(input, mapper, sumReducer[int], workers)
+			verifyResult(t, expected, result)
+		})
+	}
+
+	verifyResult(t, map[int]int{}, MapReduceParallel(nil, mapper, sumReducer[int], 4))
+}
+
+// sumReducer is a reducer that sums the integer values of a key.
+func sumReducer[K comparable](_ K, values []int) int {
+	sum := 0
+	for _, v := range values {
+		sum += v
+	}
+
+	return sum
+}
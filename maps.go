@@ -0,0 +1,288 @@
+package mapify
+
+import (
+	"cmp"
+	"regexp"
+	"slices"
+)
+
+// Numeric is a constraint that permits any integer or floating-point type.
+type Numeric interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Keys returns the keys of the provided map in an unspecified order. A nil or
+// empty map results in an empty slice.
+func Keys[K comparable, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
+
+// Values returns the values of the provided map in an unspecified order. A nil
+// or empty map results in an empty slice.
+func Values[K comparable, V any](m map[K]V) []V {
+	values := make([]V, 0, len(m))
+
+	for _, v := range m {
+		values = append(values, v)
+	}
+
+	return values
+}
+
+// ToSlice creates a slice using the provided map and the combine function to
+// convert each of its entries into an E element, reversing what FromSlice does.
+// Since maps are unordered, the order of the elements is unspecified. A nil or
+// empty map results in an empty slice.
+func ToSlice[K comparable, V any, E any](m map[K]V, combine func(k K, v V) E) []E {
+	s := make([]E, 0, len(m))
+
+	for k, v := range m {
+		s = append(s, combine(k, v))
+	}
+
+	return s
+}
+
+// GroupMapBy groups the values of the provided map by the key returned by the
+// key function for each of its entries, in the same way as
+// FromSliceWithDuplicates does for the elements of a slice. Since maps are
+// unordered, the order of the values within each slice is unspecified.
+func GroupMapBy[K1, K2 comparable, V any](m map[K1]V, key func(k K1, v V) K2) map[K2][]V {
+	groups := make(map[K2][]V)
+
+	for k, v := range m {
+		k2 := key(k, v)
+
+		groups[k2] = append(groups[k2], v)
+	}
+
+	return groups
+}
+
+// SumMaps creates a map that contains every key present in any of the provided
+// maps, mapped to the sum of the values stored for that key across all of the
+// maps. This is useful to combine counts made separately, for example by
+// several workers. Nil maps are treated as empty maps and the provided maps are
+// not modified.
+func SumMaps[K comparable, N Numeric](maps ...map[K]N) map[K]N {
+	m := make(map[K]N)
+
+	for _, mm := range maps {
+		for k, v := range mm {
+			m[k] += v
+		}
+	}
+
+	return m
+}
+
+// Merge creates a map that contains every entry of the provided maps. When a
+// key is present in several maps, the value of the last of those maps is kept,
+// so later maps override earlier ones. Nil maps are treated as empty maps and
+// the provided maps are not modified. An empty, non-nil map is returned when no
+// map is provided.
+func Merge[K comparable, V any](maps ...map[K]V) map[K]V {
+	m := make(map[K]V)
+
+	for _, mm := range maps {
+		for k, v := range mm {
+			m[k] = v
+		}
+	}
+
+	return m
+}
+
+// MergeWith creates a map that contains every key present in any of the
+// provided maps, in the same way as Merge, except that the values of a key
+// present in several maps are combined with the resolve function instead of
+// being overridden. The first value found for a key is stored as is, then
+// resolve is called with the stored value and each subsequent value found for
+// that key, in the order of the provided maps, and its result is stored.
+// Nil maps are treated as empty maps and the provided maps are not modified.
+func MergeWith[K comparable, V any](resolve func(existing, incoming V) V, maps ...map[K]V) map[K]V {
+	m := make(map[K]V)
+
+	for _, mm := range maps {
+		for k, v := range mm {
+			if existing, ok := m[k]; ok {
+				m[k] = resolve(existing, v)
+			} else {
+				m[k] = v
+			}
+		}
+	}
+
+	return m
+}
+
+// MissingKeys returns the keys of the expected slice that are not present in
+// the provided map, in the order they appear in expected. A key repeated in
+// expected is only returned once.
+func MissingKeys[K comparable, V any](m map[K]V, expected []K) []K {
+	missing := make([]K, 0)
+	seen := make(map[K]struct{}, len(expected))
+
+	for _, k := range expected {
+		if _, ok := seen[k]; ok {
+			continue
+		}
+
+		seen[k] = struct{}{}
+
+		if _, ok := m[k]; !ok {
+			missing = append(missing, k)
+		}
+	}
+
+	return missing
+}
+
+// ExtraKeys returns the keys of the provided map that are not present in the
+// expected slice, in an unspecified order.
+func ExtraKeys[K comparable, V any](m map[K]V, expected []K) []K {
+	extra := make([]K, 0)
+	want := make(map[K]struct{}, len(expected))
+
+	for _, k := range expected {
+		want[k] = struct{}{}
+	}
+
+	for k := range m {
+		if _, ok := want[k]; !ok {
+			extra = append(extra, k)
+		}
+	}
+
+	return extra
+}
+
+// FillMissing stores the value returned by the def function for each key of the
+// required slice that is not already present in the provided map, and returns
+// the map. The map is modified in place and the values of the keys already
+// present are left untouched. If the map is nil, a new map is created and
+// returned.
+func FillMissing[K comparable, V any](m map[K]V, required []K, def func(k K) V) map[K]V {
+	if m == nil {
+		m = make(map[K]V, len(required))
+	}
+
+	for _, k := range required {
+		if _, ok := m[k]; !ok {
+			m[k] = def(k)
+		}
+	}
+
+	return m
+}
+
+// KeysMatching returns the keys of the provided map that match the regular
+// expression pattern, sorted in ascending order.
+func KeysMatching[V any](m map[string]V, pattern *regexp.Regexp) []string {
+	keys := make([]string, 0)
+
+	for k := range m {
+		if pattern.MatchString(k) {
+			keys = append(keys, k)
+		}
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
+
+// SubmapMatching creates a map containing the entries of the provided map whose
+// key matches the regular expression pattern. The provided map is not modified.
+func SubmapMatching[V any](m map[string]V, pattern *regexp.Regexp) map[string]V {
+	sub := make(map[string]V)
+
+	for k, v := range m {
+		if pattern.MatchString(k) {
+			sub[k] = v
+		}
+	}
+
+	return sub
+}
+
+// SortedKeys returns the keys of the provided map sorted in ascending order.
+// A nil or empty map results in an empty slice.
+func SortedKeys[K cmp.Ordered, V any](m map[K]V) []K {
+	keys := Keys(m)
+
+	slices.Sort(keys)
+
+	return keys
+}
+
+// Filter creates a map containing the entries of the provided map for which the
+// keep function returns true. The provided map is not modified, and a nil map
+// results in an empty map.
+func Filter[K comparable, V any](m map[K]V, keep func(k K, v V) bool) map[K]V {
+	filtered := make(map[K]V)
+
+	for k, v := range m {
+		if keep(k, v) {
+			filtered[k] = v
+		}
+	}
+
+	return filtered
+}
+
+// MapValues creates a map with the same keys as the provided map, mapped to the
+// values returned by the transform function for each entry. It complements
+// FromSliceWithValue for maps that already exist, for example to turn a map of
+// users into a map of their display names. The provided map is not modified,
+// and a nil map results in an empty map.
+func MapValues[K comparable, V1, V2 any](m map[K]V1, transform func(k K, v V1) V2) map[K]V2 {
+	mapped := make(map[K]V2, len(m))
+
+	for k, v := range m {
+		mapped[k] = transform(k, v)
+	}
+
+	return mapped
+}
+
+// MapKeys creates a map with the values of the provided map, each stored under
+// the key returned by the transform function for its original key, which is
+// handy to normalize keys, for example by lowercasing them. If transform returns
+// the same key for several original keys, only one of their values is kept, and
+// which one is unspecified since maps are iterated in no particular order. To
+// keep all of the values, see MapKeysWithDuplicates. The provided map is not
+// modified, and a nil map results in an empty map.
+func MapKeys[K1, K2 comparable, V any](m map[K1]V, transform func(k K1) K2) map[K2]V {
+	mapped := make(map[K2]V, len(m))
+
+	for k, v := range m {
+		mapped[transform(k)] = v
+	}
+
+	return mapped
+}
+
+// MapKeysWithDuplicates creates a map in the same way as MapKeys, except that
+// the values whose original keys are transformed into the same key are all kept
+// in a slice, following the FromSliceWithDuplicates convention. The order of the
+// values within a slice is unspecified, since maps are iterated in no
+// particular order.
+func MapKeysWithDuplicates[K1, K2 comparable, V any](m map[K1]V, transform func(k K1) K2) map[K2][]V {
+	mapped := make(map[K2][]V)
+
+	for k, v := range m {
+		k2 := transform(k)
+		mapped[k2] = append(mapped[k2], v)
+	}
+
+	return mapped
+}
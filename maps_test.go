@@ -0,0 +1,478 @@
+package mapify
+
+import (
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestKeys verifies that the Keys function returns every key of the map and an
+// empty, non-nil slice for a nil map.
+func TestKeys(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		input    map[string]int
+		expected []string
+	}{
+		{
+			name:     "nil-map",
+			input:    nil,
+			expected: []string{},
+		},
+		{
+			name:     "several-keys",
+			input:    map[string]int{"fred": 3, "bob": 1, "alice": 2},
+			expected: []string{"alice", "bob", "fred"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result := Keys(tc.input)
+			if result == nil {
+				t.Fatal("actual is expected to be not nil")
+			}
+
+			if cap(result) != len(tc.input) {
+				t.Logf("capacity of actual is expected to be %d but was %d", len(tc.input), cap(result))
+				t.Fail()
+			}
+
+			slices.Sort(result)
+			verifySlice(t, tc.expected, result)
+		})
+	}
+}
+
+// TestValues verifies that the Values function returns every value of the map
+// and an empty, non-nil slice for a nil map.
+func TestValues(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		input    map[string]int
+		expected []int
+	}{
+		{
+			name:     "nil-map",
+			input:    nil,
+			expected: []int{},
+		},
+		{
+			name:     "several-values",
+			input:    map[string]int{"fred": 3, "bob": 1, "alice": 2, "bobby": 1},
+			expected: []int{1, 1, 2, 3},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result := Values(tc.input)
+			if result == nil {
+				t.Fatal("actual is expected to be not nil")
+			}
+
+			if cap(result) != len(tc.input) {
+				t.Logf("capacity of actual is expected to be %d but was %d", len(tc.input), cap(result))
+				t.Fail()
+			}
+
+			slices.Sort(result)
+			verifySlice(t, tc.expected, result)
+		})
+	}
+}
+
+// TestToSlice verifies that the ToSlice function converts every entry of the
+// map into an element of the slice.
+func TestToSlice(t *testing.T) {
+	combine := func(name string, id int) TestUser {
+		return TestUser{id: id, name: name}
+	}
+
+	for _, tc := range []struct {
+		name     string
+		input    map[string]int
+		expected []TestUser
+	}{
+		{
+			name:     "nil-map",
+			input:    nil,
+			expected: []TestUser{},
+		},
+		{
+			name:     "round-trip",
+			input:    FromSliceWithValue([]TestUser{testUserBob, testUserAlice}, func(u TestUser) string { return u.name }, func(u TestUser) int { return u.id }),
+			expected: []TestUser{testUserBob, testUserAlice},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result := ToSlice(tc.input, combine)
+			if result == nil {
+				t.Fatal("actual is expected to be not nil")
+			}
+
+			slices.SortFunc(result, func(a, b TestUser) int {
+				return a.id - b.id
+			})
+
+			verifySlice(t, tc.expected, result)
+		})
+	}
+}
+
+// TestGroupMapBy verifies that the GroupMapBy function groups the values of a
+// map under the keys returned by the key function.
+func TestGroupMapBy(t *testing.T) {
+	users := FromSlice([]*TestUser{&testUserBob, &testUserAlice, &testUserFred, &testUserMarie}, (*TestUser).ID)
+
+	result := GroupMapBy(users, func(_ string, u *TestUser) bool {
+		return u.id%2 == 0
+	})
+
+	verifyResultDuplicates(t, map[bool][]*TestUser{
+		true:  {&testUserAlice, &testUserMarie},
+		false: {&testUserBob, &testUserFred},
+	}, result)
+
+	verifyResultDuplicates(t, map[bool][]*TestUser{}, GroupMapBy(nil, func(_ string, u *TestUser) bool {
+		return true
+	}))
+}
+
+// TestSumMaps verifies that the SumMaps function adds the values of keys that
+// are present in several maps and keeps the values of keys present in only one.
+func TestSumMaps(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		input    []map[string]int
+		expected map[string]int
+	}{
+		{
+			name:     "no-maps",
+			input:    nil,
+			expected: map[string]int{},
+		},
+		{
+			name: "overlapping-and-disjoint-keys",
+			input: []map[string]int{
+				{"bob": 1, "alice": 2},
+				{"alice": 3, "fred": 4},
+				nil,
+				{"bob": 5, "marie": 6},
+			},
+			expected: map[string]int{
+				"bob":   6,
+				"alice": 5,
+				"fred":  4,
+				"marie": 6,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result := SumMaps(tc.input...)
+			verifyResult(t, tc.expected, result)
+		})
+	}
+}
+
+// TestMerge verifies that the Merge function combines the entries of every map,
+// lets later maps override earlier ones and leaves the provided maps untouched.
+func TestMerge(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		input    []map[string]int
+		expected map[string]int
+	}{
+		{
+			name:     "no-maps",
+			input:    nil,
+			expected: map[string]int{},
+		},
+		{
+			name:     "nil-map",
+			input:    []map[string]int{nil},
+			expected: map[string]int{},
+		},
+		{
+			name: "overlapping-and-disjoint-keys",
+			input: []map[string]int{
+				{"bob": 1, "alice": 2},
+				{"alice": 3, "fred": 4},
+				nil,
+				{"bob": 5, "marie": 6},
+			},
+			expected: map[string]int{
+				"bob":   5,
+				"alice": 3,
+				"fred":  4,
+				"marie": 6,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			before := make([]map[string]int, len(tc.input))
+			for i, m := range tc.input {
+				before[i] = maps.Clone(m)
+			}
+
+			result := Merge(tc.input...)
+			verifyResult(t, tc.expected, result)
+
+			for i, m := range tc.input {
+				if !maps.Equal(before[i], m) {
+					t.Logf("input map %d is expected to be unchanged %v but was %v", i, before[i], m)
+					t.Fail()
+				}
+			}
+		})
+	}
+}
+
+// TestMergeWith verifies that the MergeWith function only calls the resolve
+// function for keys present in several maps, with the values in the order of
+// the maps.
+func TestMergeWith(t *testing.T) {
+	calls := 0
+	sum := func(existing, incoming int) int {
+		calls++
+		return existing + incoming
+	}
+
+	verifyResult(t, map[string]int{}, MergeWith[string](sum))
+	verifyResult(t, map[string]int{
+		"bob":   6,
+		"alice": 5,
+		"fred":  4,
+		"marie": 6,
+	}, MergeWith(sum,
+		map[string]int{"bob": 1, "alice": 2},
+		map[string]int{"alice": 3, "fred": 4},
+		nil,
+		map[string]int{"bob": 5, "marie": 6},
+	))
+
+	if calls != 2 {
+		t.Logf("expected resolve to be called 2 times but it was called %d times", calls)
+		t.Fail()
+	}
+
+	input := []map[string][]int{
+		{"bob": {1}, "alice": {2}},
+		{"bob": {3}},
+		{"bob": {4, 5}},
+	}
+
+	result := MergeWith(func(existing, incoming []int) []int {
+		return append(slices.Clip(existing), incoming...)
+	}, input...)
+
+	verifySlice(t, []int{1, 3, 4, 5}, result["bob"])
+	verifySlice(t, []int{2}, result["alice"])
+	verifySlice(t, []int{1}, input[0]["bob"])
+}
+
+// TestMissingKeysAndExtraKeys verifies that the MissingKeys and ExtraKeys
+// functions report the keys absent from and unexpectedly present in a map.
+func TestMissingKeysAndExtraKeys(t *testing.T) {
+	m := map[string]int{"bob": 1, "alice": 2, "eve": 5, "mallory": 6}
+
+	for _, tc := range []struct {
+		name            string
+		expected        []string
+		expectedMissing []string
+		expectedExtra   []string
+	}{
+		{
+			name:            "missing-and-extra",
+			expected:        []string{"bob", "fred", "alice", "marie", "fred"},
+			expectedMissing: []string{"fred", "marie"},
+			expectedExtra:   []string{"eve", "mallory"},
+		},
+		{
+			name:            "exact-match",
+			expected:        []string{"alice", "bob", "eve", "mallory"},
+			expectedMissing: []string{},
+			expectedExtra:   []string{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			verifySlice(t, tc.expectedMissing, MissingKeys(m, tc.expected))
+
+			extra := ExtraKeys(m, tc.expected)
+			slices.Sort(extra)
+			verifySlice(t, tc.expectedExtra, extra)
+		})
+	}
+}
+
+// TestFillMissing verifies that the FillMissing function adds a default value
+// for each missing required key and leaves the existing keys untouched.
+func TestFillMissing(t *testing.T) {
+	def := func(k string) int {
+		return len(k)
+	}
+
+	for _, tc := range []struct {
+		name     string
+		input    map[string]int
+		required []string
+		expected map[string]int
+	}{
+		{
+			name:     "nil-map",
+			input:    nil,
+			required: []string{"bob", "alice"},
+			expected: map[string]int{"bob": 3, "alice": 5},
+		},
+		{
+			name:     "existing-and-missing-keys",
+			input:    map[string]int{"bob": 1, "alice": 2},
+			required: []string{"alice", "fred", "marie"},
+			expected: map[string]int{"bob": 1, "alice": 2, "fred": 4, "marie": 5},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result := FillMissing(tc.input, tc.required, def)
+			verifyResult(t, tc.expected, result)
+
+			if tc.input != nil {
+				verifyResult(t, tc.expected, tc.input)
+			}
+		})
+	}
+}
+
+// TestKeysMatchingAndSubmapMatching verifies that the KeysMatching and
+// SubmapMatching functions select the entries whose key matches the pattern.
+func TestKeysMatchingAndSubmapMatching(t *testing.T) {
+	m := map[string]int{
+		"db.host":    1,
+		"db.port":    2,
+		"cache.host": 3,
+		"dbx.port":   4,
+		"db":         5,
+	}
+
+	pattern := regexp.MustCompile(`^db\.`)
+
+	verifySlice(t, []string{"db.host", "db.port"}, KeysMatching(m, pattern))
+	verifyResult(t, map[string]int{"db.host": 1, "db.port": 2}, SubmapMatching(m, pattern))
+
+	none := regexp.MustCompile(`^nothing$`)
+
+	verifySlice(t, []string{}, KeysMatching(m, none))
+	verifyResult(t, map[string]int{}, SubmapMatching(m, none))
+}
+
+// TestSortedKeys verifies that the SortedKeys function returns every key of the
+// map exactly once, in ascending order.
+func TestSortedKeys(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		input    map[string]int
+		expected []string
+	}{
+		{
+			name:     "nil-map",
+			input:    nil,
+			expected: []string{},
+		},
+		{
+			name:     "several-keys",
+			input:    map[string]int{"fred": 3, "bob": 1, "marie": 4, "alice": 2},
+			expected: []string{"alice", "bob", "fred", "marie"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result := SortedKeys(tc.input)
+			if result == nil {
+				t.Fatal("actual is expected to be not nil")
+			}
+
+			verifySlice(t, tc.expected, result)
+		})
+	}
+}
+
+// TestFilter verifies that the Filter function keeps only the entries accepted
+// by the keep function, passing it both the key and the value, and does not
+// modify the provided map.
+func TestFilter(t *testing.T) {
+	m := map[string]int{"bob": 1, "alice": 2, "fred": 3, "marie": 4}
+
+	verifyResult(t, map[string]int{"alice": 2, "marie": 4}, Filter(m, func(_ string, v int) bool {
+		return v%2 == 0
+	}))
+	verifyResult(t, map[string]int{"bob": 1, "fred": 3}, Filter(m, func(k string, _ int) bool {
+		return len(k) < 5
+	}))
+	verifyResult(t, map[string]int{}, Filter(m, func(string, int) bool {
+		return false
+	}))
+	verifyResult(t, map[string]int{}, Filter(nil, func(string, int) bool {
+		return true
+	}))
+
+	verifyResult(t, map[string]int{"bob": 1, "alice": 2, "fred": 3, "marie": 4}, m)
+}
+
+// TestMapValues verifies that the MapValues function transforms every value,
+// keeps the keys, and does not modify the provided map.
+func TestMapValues(t *testing.T) {
+	m := map[int]*TestUser{1: &testUserBob, 2: &testUserAlice, 3: &testUserFred}
+
+	verifyResult(t, map[int]string{1: "bob", 2: "alice", 3: "fred"}, MapValues(m, func(_ int, u *TestUser) string {
+		return u.name
+	}))
+	verifyResult(t, map[int]bool{1: true, 2: false, 3: false}, MapValues(m, func(k int, u *TestUser) bool {
+		return k == u.id && u.name == "bob"
+	}))
+	verifyResult(t, map[int]string{}, MapValues(map[int]*TestUser(nil), func(_ int, u *TestUser) string {
+		return u.name
+	}))
+
+	verifyResult(t, map[int]*TestUser{1: &testUserBob, 2: &testUserAlice, 3: &testUserFred}, m)
+}
+
+// TestMapKeys verifies that the MapKeys function stores every value under its
+// transformed key and keeps one of the values of colliding keys, and that the
+// MapKeysWithDuplicates function keeps all of them.
+func TestMapKeys(t *testing.T) {
+	m := map[string]int{"Bob": 1, "ALICE": 2, "bob": 3, "Fred": 4}
+
+	verifyResult(t, map[string]int{"Bob!": 1, "ALICE!": 2, "bob!": 3, "Fred!": 4}, MapKeys(m, func(k string) string {
+		return k + "!"
+	}))
+
+	lower := MapKeys(m, strings.ToLower)
+	if len(lower) != 3 || lower["alice"] != 2 || lower["fred"] != 4 {
+		t.Logf("expected the keys alice, bob and fred with alice=2 and fred=4 but got %v", lower)
+		t.Fail()
+	}
+
+	if v := lower["bob"]; v != 1 && v != 3 {
+		t.Logf("expected the key bob to hold 1 or 3 but got %d", v)
+		t.Fail()
+	}
+
+	verifyResult(t, map[int]int{}, MapKeys(map[string]int(nil), func(k string) int {
+		return len(k)
+	}))
+
+	groups := MapKeysWithDuplicates(m, strings.ToLower)
+	if len(groups) != 3 {
+		t.Fatalf("expected 3 keys but got %d", len(groups))
+	}
+
+	bobs := groups["bob"]
+	slices.Sort(bobs)
+
+	verifySlice(t, []int{1, 3}, bobs)
+	verifySlice(t, []int{2}, groups["alice"])
+	verifySlice(t, []int{4}, groups["fred"])
+
+	if len(MapKeysWithDuplicates(map[string]int(nil), strings.ToLower)) != 0 {
+		t.Log("expected a nil map to result in an empty map")
+		t.Fail()
+	}
+
+	verifyResult(t, map[string]int{"Bob": 1, "ALICE": 2, "bob": 3, "Fred": 4}, m)
+}
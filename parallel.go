@@ -0,0 +1,133 @@
+package mapify
+
+import (
+	"sync"
+)
+
+// FromSliceParallelReduce creates a map of keys K to accumulated values A using
+// the provided slice of E elements, aggregating the elements of each key in
+// parallel. The slice is split into contiguous chunks, one per worker, and each
+// worker folds the elements of its chunk into a partial accumulator per key,
+// starting from the value returned by seed and applying step for each element.
+// The partial accumulators of each key are then merged with combine, in the
+// order of the chunks. The result matches a sequential aggregation only if
+// combining the folds of two adjacent chunks always equals the fold of their
+// concatenation, as is the case for a step of acc+e with a combine of a+b. It
+// is not enough for combine to be associative: with a step of acc+1, a combine
+// of max and a seed of 0, the result is the largest count of a chunk rather
+// than the total count. If workers is less than one, a single worker is used.
+func FromSliceParallelReduce[E any, K comparable, A any](s []E, key func(e E) K, seed func() A, step func(acc A, e E) A, combine func(a, b A) A, workers int) map[K]A {
+	chunks := splitChunks(s, workers)
+	partials := make([]map[K]A, len(chunks))
+
+	var wg sync.WaitGroup
+
+	for i, chunk := range chunks {
+		wg.Add(1)
+
+		go func(i int, chunk []E) {
+			defer wg.Done()
+
+			partial := make(map[K]A)
+
+			for _, e := range chunk {
+				k := key(e)
+
+				acc, ok := partial[k]
+				if !ok {
+					acc = seed()
+				}
+
+				partial[k] = step(acc, e)
+			}
+
+			partials[i] = partial
+		}(i, chunk)
+	}
+
+	wg.Wait()
+
+	m := make(map[K]A)
+
+	for _, partial := range partials {
+		for k, v := range partial {
+			if acc, ok := m[k]; ok {
+				m[k] = combine(acc, v)
+			} else {
+				m[k] = v
+			}
+		}
+	}
+
+	return m
+}
+
+// FromSliceParallel creates a map in the same way as FromSlice, except that the
+// key function is called concurrently by the provided number of workers, each
+// handling a contiguous chunk of the slice. The map is then filled in the order
+// of the slice, so when several elements have the same key, the last one wins,
+// exactly as with FromSlice. This only pays off when the key function is
+// expensive enough to outweigh the cost of the goroutines and of storing the
+// keys. The key function must be safe for concurrent use. If workers is less
+// than one, a single worker is used.
+func FromSliceParallel[E any, K comparable](s []E, key func(e E) K, workers int) map[K]E {
+	keys := make([]K, len(s))
+
+	var wg sync.WaitGroup
+
+	for _, b := range chunkBounds(len(s), workers) {
+		wg.Add(1)
+
+		go func(lo, hi int) {
+			defer wg.Done()
+
+			for i := lo; i < hi; i++ {
+				keys[i] = key(s[i])
+			}
+		}(b[0], b[1])
+	}
+
+	wg.Wait()
+
+	m := make(map[K]E, len(s))
+
+	for i, e := range s {
+		m[keys[i]] = e
+	}
+
+	return m
+}
+
+// splitChunks splits the provided slice into at most n contiguous chunks of
+// nearly equal length, as delimited by chunkBounds.
+func splitChunks[E any](s []E, n int) [][]E {
+	bounds := chunkBounds(len(s), n)
+	chunks := make([][]E, 0, len(bounds))
+
+	for _, b := range bounds {
+		chunks = append(chunks, s[b[0]:b[1]])
+	}
+
+	return chunks
+}
+
+// chunkBounds returns the [lo, hi) offsets of at most n contiguous chunks of
+// nearly equal length covering a slice of the provided length. If n is less
+// than one, a single chunk is returned.
+func chunkBounds(length, n int) [][2]int {
+	if n < 1 {
+		n = 1
+	}
+
+	if n > length {
+		n = length
+	}
+
+	bounds := make([][2]int, 0, n)
+
+	for i := 0; i < n; i++ {
+		bounds = append(bounds, [2]int{i * length / n, (i + 1) * length / n})
+	}
+
+	return bounds
+}
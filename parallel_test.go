@@ -0,0 +1,109 @@
+package mapify
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"strconv"
+	"testing"
+)
+
+// TestFromSliceParallelReduce verifies that the FromSliceParallelReduce
+// function produces the same result regardless of the number of workers when
+// the reducer is commutative and associative.
+func TestFromSliceParallelReduce(t *testing.T) {
+	input := make([]int, 1000)
+	for i := range input {
+		input[i] = i
+	}
+
+	key := func(e int) int { return e % 7 }
+	seed := func() int { return 0 }
+	step := func(acc, e int) int { return acc + e }
+	combine := func(a, b int) int { return a + b }
+
+	for _, tc := range []struct {
+		name    string
+		input   []int
+		workers int
+	}{
+		{name: "single-worker", input: input, workers: 1},
+		{name: "several-workers", input: input, workers: 4},
+		{name: "uneven-chunks", input: input, workers: 7},
+		{name: "more-workers-than-elements", input: input[:3], workers: 8},
+		{name: "no-workers", input: input, workers: 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sequential := make(map[int]int)
+			for _, e := range tc.input {
+				sequential[key(e)] += e
+			}
+
+			result := FromSliceParallelReduce(tc.input, key, seed, step, combine, tc.workers)
+			verifyResult(t, sequential, result)
+		})
+	}
+
+	result := FromSliceParallelReduce[int, int, int](nil, key, seed, step, combine, 4)
+	verifyResult(t, map[int]int{}, result)
+}
+
+// TestFromSliceParallel verifies that the FromSliceParallel function builds the
+// same map as FromSlice, including the last-wins semantics on key collisions,
+// regardless of the number of workers.
+func TestFromSliceParallel(t *testing.T) {
+	input := make([]int, 1000)
+	for i := range input {
+		input[i] = i
+	}
+
+	key := func(e int) int { return e % 97 }
+	expected := FromSlice(input, key)
+
+	for _, workers := range []int{0, 1, 3, 8, 5000} {
+		t.Run(strconv.Itoa(workers)+"-workers", func(t *testing.T) {
+			verifyResult(t, expected, FromSliceParallel(input, key, workers))
+		})
+	}
+
+	verifyResult(t, map[int]int{}, FromSliceParallel(nil, key, 4))
+}
+
+// benchmarkInput is the slice mapified by the FromSlice and FromSliceParallel
+// benchmarks.
+var benchmarkInput = func() []int {
+	s := make([]int, 100000)
+	for i := range s {
+		s[i] = i
+	}
+
+	return s
+}()
+
+// hashingKey is a moderately expensive key function, hashing the element with
+// SHA-256, used to compare FromSlice and FromSliceParallel.
+func hashingKey(e int) [sha256.Size]byte {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], uint64(e))
+
+	return sha256.Sum256(b[:])
+}
+
+// BenchmarkFromSlice_HashingKey measures FromSlice with an expensive key
+// function, as a baseline for BenchmarkFromSliceParallel_HashingKey.
+func BenchmarkFromSlice_HashingKey(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		FromSlice(benchmarkInput, hashingKey)
+	}
+}
+
+// BenchmarkFromSliceParallel_HashingKey measures FromSliceParallel with an
+// expensive key function and various numbers of workers.
+func BenchmarkFromSliceParallel_HashingKey(b *testing.B) {
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(strconv.Itoa(workers)+"-workers", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				FromSliceParallel(benchmarkInput, hashingKey, workers)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package mapify
+
+import (
+	"cmp"
+	"fmt"
+	"html"
+	"strings"
+)
+
+// GroupsToHTMLTable renders the provided map of grouped elements as an HTML
+// table, which is useful to quickly visualize a grouping in internal tools. The
+// table has a header row followed by one row per key, sorted in ascending
+// order, where the first cell holds the key and the second cell holds the
+// elements of the key, rendered with the render function and joined with
+// commas. The keys and rendered elements are HTML-escaped.
+func GroupsToHTMLTable[K cmp.Ordered, E any](m map[K][]E, render func(e E) string) string {
+	keys := SortedKeys(m)
+
+	var b strings.Builder
+
+	b.WriteString("<table>\n")
+	b.WriteString("<tr><th>Key</th><th>Elements</th></tr>\n")
+
+	for _, k := range keys {
+		rendered := make([]string, 0, len(m[k]))
+		for _, e := range m[k] {
+			rendered = append(rendered, html.EscapeString(render(e)))
+		}
+
+		fmt.Fprintf(&b, "<tr><td>%s</td><td>%s</td></tr>\n", html.EscapeString(fmt.Sprint(k)), strings.Join(rendered, ", "))
+	}
+
+	b.WriteString("</table>\n")
+
+	return b.String()
+}
+
+// Snapshot renders the provided map of grouped elements as a canonical
+// multi-line string that is suitable for storing as a golden file and diffing
+// across runs. Each key is written on its own line, in ascending key order, and
+// is followed by one line per element, rendered with the render function and
+// indented with a tab, in the order of the key's slice. Keys and elements are
+// quoted with Go syntax, so that rendered strings spanning several lines cannot
+// alter the structure of the output. Equivalent groupings always produce
+// byte-identical output.
+func Snapshot[K cmp.Ordered, E any](m map[K][]E, render func(e E) string) string {
+	keys := SortedKeys(m)
+
+	var b strings.Builder
+
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%q\n", fmt.Sprint(k))
+
+		for _, e := range m[k] {
+			fmt.Fprintf(&b, "\t%q\n", render(e))
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,87 @@
+package mapify
+
+import (
+	"testing"
+)
+
+// TestGroupsToHTMLTable verifies that the GroupsToHTMLTable function renders
+// one row per key, in ascending key order, and escapes the rendered content.
+func TestGroupsToHTMLTable(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		input    map[string][]string
+		expected string
+	}{
+		{
+			name:  "empty-map",
+			input: map[string][]string{},
+			expected: "<table>\n" +
+				"<tr><th>Key</th><th>Elements</th></tr>\n" +
+				"</table>\n",
+		},
+		{
+			name: "sorted-rows",
+			input: map[string][]string{
+				"girls": {"alice", "marie"},
+				"boys":  {"bob", "fred"},
+			},
+			expected: "<table>\n" +
+				"<tr><th>Key</th><th>Elements</th></tr>\n" +
+				"<tr><td>boys</td><td>bob, fred</td></tr>\n" +
+				"<tr><td>girls</td><td>alice, marie</td></tr>\n" +
+				"</table>\n",
+		},
+		{
+			name: "escaped-content",
+			input: map[string][]string{
+				"<b>": {"<script>alert('x')</script>", "a & b"},
+			},
+			expected: "<table>\n" +
+				"<tr><th>Key</th><th>Elements</th></tr>\n" +
+				"<tr><td>&lt;b&gt;</td><td>&lt;script&gt;alert(&#39;x&#39;)&lt;/script&gt;, a &amp; b</td></tr>\n" +
+				"</table>\n",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result := GroupsToHTMLTable(tc.input, func(s string) string { return s })
+			if result != tc.expected {
+				t.Fatalf("expected:\n%s\nbut got:\n%s", tc.expected, result)
+			}
+		})
+	}
+}
+
+// TestSnapshot verifies that the Snapshot function renders groupings in a
+// canonical form that is identical for equivalent groupings.
+func TestSnapshot(t *testing.T) {
+	render := func(u *TestUser) string {
+		return u.name
+	}
+
+	newGroups := func() map[int][]*TestUser {
+		return map[int][]*TestUser{
+			2: {&testUserAlice, &testUserMarie},
+			1: {&testUserBob, &testUserFred},
+			3: {},
+		}
+	}
+
+	expected := "\"1\"\n" +
+		"\t\"bob\"\n" +
+		"\t\"fred\"\n" +
+		"\"2\"\n" +
+		"\t\"alice\"\n" +
+		"\t\"marie\"\n" +
+		"\"3\"\n"
+
+	for i := 0; i < 10; i++ {
+		if result := Snapshot(newGroups(), render); result != expected {
+			t.Fatalf("expected:\n%s\nbut got:\n%s", expected, result)
+		}
+	}
+
+	multiline := Snapshot(map[string][]string{"a": {"line1\nline2"}}, func(s string) string { return s })
+	if multiline != "\"a\"\n\t\"line1\\nline2\"\n" {
+		t.Fatalf("unexpected rendering of a multi-line element: %q", multiline)
+	}
+}
@@ -0,0 +1,36 @@
+package mapify
+
+// Rollup3Reduce aggregates the elements of the provided slice into a three-level
+// nested map, keyed by the values returned by the k1, k2 and k3 functions for
+// each element. The elements sharing all three keys are folded into a single
+// accumulated value, starting from the value returned by seed and applying step
+// for each element in the order of the slice. The inner maps are only created
+// for the key combinations that are present in the slice.
+func Rollup3Reduce[E any, K1, K2, K3 comparable, A any](s []E, k1 func(e E) K1, k2 func(e E) K2, k3 func(e E) K3, seed func() A, step func(acc A, e E) A) map[K1]map[K2]map[K3]A {
+	m := make(map[K1]map[K2]map[K3]A)
+
+	for _, e := range s {
+		key1, key2, key3 := k1(e), k2(e), k3(e)
+
+		level2, ok := m[key1]
+		if !ok {
+			level2 = make(map[K2]map[K3]A)
+			m[key1] = level2
+		}
+
+		level3, ok := level2[key2]
+		if !ok {
+			level3 = make(map[K3]A)
+			level2[key2] = level3
+		}
+
+		acc, ok := level3[key3]
+		if !ok {
+			acc = seed()
+		}
+
+		level3[key3] = step(acc, e)
+	}
+
+	return m
+}
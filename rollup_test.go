@@ -0,0 +1,60 @@
+package mapify
+
+import (
+	"testing"
+)
+
+// TestRollup3Reduce verifies that the Rollup3Reduce function aggregates the
+// elements into the expected three-level nested map.
+func TestRollup3Reduce(t *testing.T) {
+	type Sale struct {
+		year    int
+		region  string
+		product string
+		amount  int
+	}
+
+	input := []Sale{
+		{year: 2023, region: "east", product: "apple", amount: 10},
+		{year: 2023, region: "east", product: "apple", amount: 5},
+		{year: 2023, region: "east", product: "pear", amount: 3},
+		{year: 2023, region: "west", product: "apple", amount: 7},
+		{year: 2024, region: "east", product: "pear", amount: 1},
+	}
+
+	result := Rollup3Reduce(input, func(s Sale) int {
+		return s.year
+	}, func(s Sale) string {
+		return s.region
+	}, func(s Sale) string {
+		return s.product
+	}, func() int {
+		return 0
+	}, func(acc int, s Sale) int {
+		return acc + s.amount
+	})
+
+	expected := map[int]map[string]map[string]int{
+		2023: {
+			"east": {"apple": 15, "pear": 3},
+			"west": {"apple": 7},
+		},
+		2024: {
+			"east": {"pear": 1},
+		},
+	}
+
+	if len(result) != len(expected) {
+		t.Fatalf("expected %d years but got %v", len(expected), result)
+	}
+
+	for year, regions := range expected {
+		if len(result[year]) != len(regions) {
+			t.Fatalf("expected %d regions for %d but got %v", len(regions), year, result[year])
+		}
+
+		for region, products := range regions {
+			verifyResult(t, products, result[year][region])
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package mapify
+
+import (
+	"cmp"
+	"container/heap"
+	"math"
+	"math/rand"
+	"slices"
+)
+
+// SampleAndCountByKey groups the elements of the provided slice by key, as
+// determined by the key function, and returns, for each key, a uniform random
+// sample of at most sampleSize of its elements along with the total number of
+// its elements. The samples are drawn with reservoir sampling using the provided
+// random number generator, which is consumed in the order of the elements, so a
+// generator seeded with the same value always produces the same samples. If
+// sampleSize is less than one, the samples are empty.
+func SampleAndCountByKey[E any, K comparable](s []E, key func(e E) K, sampleSize int, rng *rand.Rand) map[K]struct {
+	Sample []E
+	Total  int
+} {
+	reservoirs := make(map[K]*reservoir[E])
+
+	for _, e := range s {
+		k := key(e)
+
+		r, ok := reservoirs[k]
+		if !ok {
+			r = &reservoir[E]{size: sampleSize, sample: make([]E, 0)}
+			reservoirs[k] = r
+		}
+
+		r.add(e, rng)
+	}
+
+	m := make(map[K]struct {
+		Sample []E
+		Total  int
+	}, len(reservoirs))
+
+	for k, r := range reservoirs {
+		m[k] = struct {
+			Sample []E
+			Total  int
+		}{Sample: r.sample, Total: r.seen}
+	}
+
+	return m
+}
+
+// reservoir holds a uniform random sample of at most size of the elements added
+// to it.
+type reservoir[E any] struct {
+	size   int
+	seen   int
+	sample []E
+}
+
+// add offers the element e to the reservoir, which keeps it with a probability
+// of size divided by the number of elements seen so far.
+func (r *reservoir[E]) add(e E, rng *rand.Rand) {
+	r.seen++
+
+	if r.size < 1 {
+		return
+	}
+
+	if len(r.sample) < r.size {
+		r.sample = append(r.sample, e)
+		return
+	}
+
+	if j := rng.Intn(r.seen); j < r.size {
+		r.sample[j] = e
+	}
+}
+
+// WeightedSamplerByKey maintains, for each key, a weighted random sample of at
+// most n of the elements added to it, without buffering the whole stream. It
+// uses the A-Res algorithm by Efraimidis and Spirakis: each element is given
+// the score u^(1/w), where u is drawn uniformly from [0, 1) and w is its
+// weight, and the n elements with the highest scores of each key are kept, so
+// elements with higher weights are more likely to be sampled. A
+// WeightedSamplerByKey is not safe for concurrent use.
+type WeightedSamplerByKey[E any, K comparable] struct {
+	key     func(e E) K
+	weight  func(e E) float64
+	n       int
+	rng     *rand.Rand
+	samples map[K]*minHeap[scored[E]]
+}
+
+// scored is an element along with the score it was given by a
+// WeightedSamplerByKey.
+type scored[E any] struct {
+	element E
+	score   float64
+}
+
+// NewWeightedSamplerByKey creates a WeightedSamplerByKey that keeps at most n
+// elements per key, using the key function to determine the key of each element,
+// the weight function to determine its weight, and the provided random number
+// generator to draw the scores, so a generator seeded with the same value always
+// produces the same samples for the same stream.
+func NewWeightedSamplerByKey[E any, K comparable](key func(e E) K, weight func(e E) float64, n int, rng *rand.Rand) *WeightedSamplerByKey[E, K] {
+	return &WeightedSamplerByKey[E, K]{
+		key:     key,
+		weight:  weight,
+		n:       n,
+		rng:     rng,
+		samples: make(map[K]*minHeap[scored[E]]),
+	}
+}
+
+// Add offers the element e to the sample of its key. Elements whose weight is
+// not positive are never sampled, and their key only appears in the result if
+// other elements of that key are sampled.
+func (w *WeightedSamplerByKey[E, K]) Add(e E) {
+	weight := w.weight(e)
+	if weight <= 0 || w.n < 1 {
+		return
+	}
+
+	k := w.key(e)
+
+	h, ok := w.samples[k]
+	if !ok {
+		h = &minHeap[scored[E]]{less: func(a, b scored[E]) bool {
+			return a.score < b.score
+		}}
+		w.samples[k] = h
+	}
+
+	s := scored[E]{element: e, score: math.Pow(w.rng.Float64(), 1/weight)}
+
+	if h.Len() < w.n {
+		heap.Push(h, s)
+		return
+	}
+
+	if h.less(h.elements[0], s) {
+		h.elements[0] = s
+		heap.Fix(h, 0)
+	}
+}
+
+// Result returns the current sample of each key, ordered from the highest to
+// the lowest score. The returned map and slices are copies that the caller is
+// free to modify.
+func (w *WeightedSamplerByKey[E, K]) Result() map[K][]E {
+	m := make(map[K][]E, len(w.samples))
+
+	for k, h := range w.samples {
+		sorted := slices.Clone(h.elements)
+		slices.SortFunc(sorted, func(a, b scored[E]) int {
+			return cmp.Compare(b.score, a.score)
+		})
+
+		sample := make([]E, len(sorted))
+		for i, s := range sorted {
+			sample[i] = s.element
+		}
+
+		m[k] = sample
+	}
+
+	return m
+}
@@ -0,0 +1,110 @@
+package mapify
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// TestSampleAndCountByKey verifies that the SampleAndCountByKey function
+// reports the true count of each key along with a bounded sample of its
+// elements, reproducibly for a seeded random number generator.
+func TestSampleAndCountByKey(t *testing.T) {
+	input := make([]int, 1000)
+	for i := range input {
+		input[i] = i
+	}
+
+	key := func(e int) int {
+		if e < 900 {
+			return 0
+		}
+
+		return 1
+	}
+
+	first := SampleAndCountByKey(input, key, 10, rand.New(rand.NewSource(7)))
+	second := SampleAndCountByKey(input, key, 10, rand.New(rand.NewSource(7)))
+
+	if len(first) != 2 {
+		t.Fatalf("expected 2 keys but got %d", len(first))
+	}
+
+	for k, total := range map[int]int{0: 900, 1: 100} {
+		if first[k].Total != total {
+			t.Logf("total of key %d is expected to be %d but was %d", k, total, first[k].Total)
+			t.Fail()
+		}
+
+		if len(first[k].Sample) != 10 {
+			t.Logf("sample of key %d is expected to have 10 elements but had %d", k, len(first[k].Sample))
+			t.Fail()
+		}
+
+		for _, e := range first[k].Sample {
+			if key(e) != k {
+				t.Logf("sample of key %d is not expected to contain %d", k, e)
+				t.Fail()
+			}
+		}
+
+		verifySlice(t, first[k].Sample, second[k].Sample)
+	}
+
+	small := SampleAndCountByKey([]int{1, 2, 901}, key, 10, rand.New(rand.NewSource(7)))
+	verifySlice(t, []int{1, 2}, small[0].Sample)
+	verifySlice(t, []int{901}, small[1].Sample)
+}
+
+// TestWeightedSamplerByKey verifies that the WeightedSamplerByKey produces
+// reproducible samples for a seeded random number generator and favours the
+// elements with higher weights.
+func TestWeightedSamplerByKey(t *testing.T) {
+	type Item struct {
+		group  string
+		name   string
+		weight float64
+	}
+
+	input := []Item{
+		{group: "a", name: "heavy", weight: 9},
+		{group: "a", name: "light", weight: 1},
+		{group: "a", name: "never", weight: 0},
+		{group: "b", name: "only", weight: 2},
+		{group: "c", name: "never", weight: 0},
+	}
+
+	key := func(i Item) string { return i.group }
+	weight := func(i Item) float64 { return i.weight }
+
+	sample := func(seed int64) map[string][]Item {
+		sampler := NewWeightedSamplerByKey(key, weight, 1, rand.New(rand.NewSource(seed)))
+		for _, i := range input {
+			sampler.Add(i)
+		}
+
+		return sampler.Result()
+	}
+
+	first, second := sample(3), sample(3)
+
+	if len(first) != 2 {
+		t.Fatalf("expected 2 keys but got %v", first)
+	}
+
+	for k, v := range first {
+		verifySlice(t, v, second[k])
+	}
+
+	verifySlice(t, []Item{input[3]}, first["b"])
+
+	heavy := 0
+	for seed := int64(0); seed < 1000; seed++ {
+		if sample(seed)["a"][0].name == "heavy" {
+			heavy++
+		}
+	}
+
+	if heavy < 850 || heavy > 950 {
+		t.Fatalf("heavy element is expected to be sampled about 900 times out of 1000 but was sampled %d times", heavy)
+	}
+}
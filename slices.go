@@ -1,12 +1,39 @@
 package mapify
 
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrDuplicateKey is the error wrapped by the errors returned when elements
+// that are expected to have unique keys collide.
+var ErrDuplicateKey = errors.New("duplicate key")
+
+// ErrTooManyKeys is the error wrapped by the errors returned when the number of
+// distinct keys exceeds the provided limit.
+var ErrTooManyKeys = errors.New("too many distinct keys")
+
+// ErrIncomparable is the error wrapped by the errors returned when elements
+// that must be compared hold values that cannot be compared.
+var ErrIncomparable = errors.New("incomparable elements")
+
+// contextCheckInterval is the number of elements processed by FromSliceContext
+// between checks of its context.
+const contextCheckInterval = 1024
+
 // FromSlice creates a map using the provided slice of E elements and the
 // key function to determine the map key for each of the elements in the slice.
 // If the key function returns the same key for multiple elements, the previous
 // element stored with the duplicated key will be overwritten. To create a map
-// that can handle duplicate keys, see FromSliceWithDuplicates.
+// that can handle duplicate keys, see FromSliceWithDuplicates. The map is sized
+// for the length of the slice up front, which avoids growing it repeatedly.
+// To create a map from an array, pass a slice of the whole array, as in
+// FromSlice(a[:], key), which does not copy the array.
 func FromSlice[E any, K comparable](s []E, key func(e E) K) map[K]E {
-	m := make(map[K]E)
+	m := make(map[K]E, len(s))
 
 	for _, e := range s {
 		k := key(e)
@@ -17,6 +44,38 @@ func FromSlice[E any, K comparable](s []E, key func(e E) K) map[K]E {
 	return m
 }
 
+// FromSliceWithValue creates a map using the provided slice of E elements, the
+// key function to determine the map key and the value function to determine the
+// map value for each of the elements in the slice. Both functions receive the
+// same element. If the key function returns the same key for multiple elements,
+// the value stored for the previous element is overwritten, as with FromSlice.
+func FromSliceWithValue[E any, K comparable, V any](s []E, key func(e E) K, value func(e E) V) map[K]V {
+	m := make(map[K]V)
+
+	for _, e := range s {
+		m[key(e)] = value(e)
+	}
+
+	return m
+}
+
+// FromSliceSorted creates a map in the same way as FromSlice and returns its
+// entries as two parallel slices, holding the keys sorted in ascending order and
+// their corresponding elements. If the key function returns the same key for
+// multiple elements, only the last one is kept, as with FromSlice, so the
+// slices hold one entry per unique key.
+func FromSliceSorted[E any, K cmp.Ordered](s []E, key func(e E) K) ([]K, []E) {
+	m := FromSlice(s, key)
+	keys := SortedKeys(m)
+	elements := make([]E, len(keys))
+
+	for i, k := range keys {
+		elements[i] = m[k]
+	}
+
+	return keys, elements
+}
+
 // FromSliceWithDuplicates creates a map using the provided slice of E elements
 // and the key function to determine the map key for each of the elements in the
 // slice. The slice elements are stored slices in the map, so if the key
@@ -33,3 +92,590 @@ func FromSliceWithDuplicates[E any, K comparable](s []E, walk func(e E) K) map[K
 
 	return m
 }
+
+// FromSliceWithDuplicatesCap creates a map in the same way as
+// FromSliceWithDuplicates, but reduces the number of allocations when the
+// number of elements per key can be estimated. The slice of each key is
+// allocated with a capacity of perKeyHint, and the map is sized up front for
+// the number of keys expected given that hint. A hint that is too small only
+// costs the reallocations it was meant to avoid, while a hint that is too large
+// wastes memory. If perKeyHint is less than one, a hint of one is used.
+func FromSliceWithDuplicatesCap[E any, K comparable](s []E, key func(e E) K, perKeyHint int) map[K][]E {
+	perKeyHint = max(perKeyHint, 1)
+	m := make(map[K][]E, len(s)/perKeyHint)
+
+	for _, e := range s {
+		k := key(e)
+
+		bucket, ok := m[k]
+		if !ok {
+			bucket = make([]E, 0, perKeyHint)
+		}
+
+		m[k] = append(bucket, e)
+	}
+
+	return m
+}
+
+// GroupBy groups the elements of the provided slice by the key returned by the
+// key function for each of them. It is another name for FromSliceWithDuplicates
+// and behaves identically.
+func GroupBy[E any, K comparable](s []E, key func(e E) K) map[K][]E {
+	return FromSliceWithDuplicates(s, key)
+}
+
+// FromSliceValidated creates a map in the same way as FromSlice, except that
+// each element is first passed to the validate function and only the elements
+// for which it returns a nil error are stored in the map. The key function is
+// never called for invalid elements. The errors returned by the validate
+// function are collected, wrapped with the index of the offending element, and
+// returned in the order the elements appear in the slice.
+func FromSliceValidated[E any, K comparable](s []E, key func(e E) K, validate func(e E) error) (map[K]E, []error) {
+	m := make(map[K]E)
+
+	var errs []error
+
+	for i, e := range s {
+		if err := validate(e); err != nil {
+			errs = append(errs, fmt.Errorf("element at index %d: %w", i, err))
+			continue
+		}
+
+		m[key(e)] = e
+	}
+
+	return m, errs
+}
+
+// FromSliceE creates a map in the same way as FromSlice, except that the key
+// function can fail. Processing stops at the first error returned by the key
+// function, in which case the map built from the preceding elements is returned
+// along with the error, wrapped with the index of the offending element.
+func FromSliceE[E any, K comparable](s []E, key func(e E) (K, error)) (map[K]E, error) {
+	m := make(map[K]E)
+
+	for i, e := range s {
+		k, err := key(e)
+		if err != nil {
+			return m, fmt.Errorf("element at index %d: %w", i, err)
+		}
+
+		m[k] = e
+	}
+
+	return m, nil
+}
+
+// FromSliceStrict creates a map in the same way as FromSlice, except that it
+// fails as soon as the key function returns the same key for two elements,
+// rather than overwriting the previous element. In that case, the map built from
+// the preceding elements is returned along with an error that wraps
+// ErrDuplicateKey and names the key and the indices of both elements.
+func FromSliceStrict[E any, K comparable](s []E, key func(e E) K) (map[K]E, error) {
+	m := make(map[K]E)
+	indices := make(map[K]int)
+
+	for i, e := range s {
+		k := key(e)
+
+		if j, ok := indices[k]; ok {
+			return m, fmt.Errorf("%w %v at indices %d and %d", ErrDuplicateKey, k, j, i)
+		}
+
+		m[k] = e
+		indices[k] = i
+	}
+
+	return m, nil
+}
+
+// FromSliceContext creates a map in the same way as FromSlice, except that it
+// stops early if the provided context is cancelled. The context is checked
+// before processing the first element and then every contextCheckInterval
+// elements, so a cancellation is noticed within that many calls to the key
+// function. When cancelled, the map built from the elements processed so far
+// is returned along with the context's error.
+func FromSliceContext[E any, K comparable](ctx context.Context, s []E, key func(e E) K) (map[K]E, error) {
+	m := make(map[K]E)
+
+	for i, e := range s {
+		if i%contextCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return m, err
+			}
+		}
+
+		m[key(e)] = e
+	}
+
+	return m, nil
+}
+
+// FromSliceMapE creates a map of keys K to values V using the provided slice of
+// E elements, the key function to determine the map key and the value function
+// to determine the map value for each element. For each element, the key
+// function is called first and the value function is only called if the key
+// function succeeded. Processing stops at the first error returned by either
+// function, in which case the map built from the preceding elements is returned
+// along with the error, wrapped with the index of the offending element.
+func FromSliceMapE[E any, K comparable, V any](s []E, key func(e E) (K, error), value func(e E) (V, error)) (map[K]V, error) {
+	m := make(map[K]V)
+
+	for i, e := range s {
+		k, err := key(e)
+		if err != nil {
+			return m, fmt.Errorf("element at index %d: key: %w", i, err)
+		}
+
+		v, err := value(e)
+		if err != nil {
+			return m, fmt.Errorf("element at index %d: value: %w", i, err)
+		}
+
+		m[k] = v
+	}
+
+	return m, nil
+}
+
+// FromSliceWeightedMultiKey creates a map using the provided slice of E
+// elements and the keyed function, which returns the keys under which each
+// element should be stored along with the weight of its membership for each of
+// those keys. An element is appended, with its weight, to the slice of every
+// key returned for it, so the slices preserve the order of the elements in the
+// provided slice. An element for which keyed returns no keys is not stored.
+func FromSliceWeightedMultiKey[E any, K comparable](s []E, keyed func(e E) map[K]float64) map[K][]struct {
+	Elem   E
+	Weight float64
+} {
+	m := make(map[K][]struct {
+		Elem   E
+		Weight float64
+	})
+
+	for _, e := range s {
+		for k, w := range keyed(e) {
+			m[k] = append(m[k], struct {
+				Elem   E
+				Weight float64
+			}{Elem: e, Weight: w})
+		}
+	}
+
+	return m
+}
+
+// FromSliceWithDuplicatesE creates a map in the same way as
+// FromSliceWithDuplicates, except that the key function can fail. Processing
+// stops at the first error returned by the key function, in which case the map
+// built from the preceding elements is returned along with the error, wrapped
+// with the index of the offending element.
+func FromSliceWithDuplicatesE[E any, K comparable](s []E, key func(e E) (K, error)) (map[K][]E, error) {
+	m := make(map[K][]E)
+
+	for i, e := range s {
+		k, err := key(e)
+		if err != nil {
+			return m, fmt.Errorf("element at index %d: %w", i, err)
+		}
+
+		m[k] = append(m[k], e)
+	}
+
+	return m, nil
+}
+
+// FromSliceWithDuplicatesCollectErrors creates a map in the same way as
+// FromSliceWithDuplicatesE, except that it does not stop at the first error.
+// Elements for which the key function fails are left out of the map, and the
+// errors, wrapped with the index of the offending element, are returned in the
+// order the elements appear in the slice.
+func FromSliceWithDuplicatesCollectErrors[E any, K comparable](s []E, key func(e E) (K, error)) (map[K][]E, []error) {
+	m := make(map[K][]E)
+
+	var errs []error
+
+	for i, e := range s {
+		k, err := key(e)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("element at index %d: %w", i, err))
+			continue
+		}
+
+		m[k] = append(m[k], e)
+	}
+
+	return m, errs
+}
+
+// FlatMap creates a map of keys K to slices of V values using the provided
+// slice of E elements and the expand function, which returns the key and value
+// pairs that each element contributes. Every pair's value is appended to the
+// slice of its key, so an element can contribute to several keys, several times
+// to the same key, or to none at all.
+func FlatMap[E any, K comparable, V any](s []E, expand func(e E) []struct {
+	Key   K
+	Value V
+}) map[K][]V {
+	m := make(map[K][]V)
+
+	for _, e := range s {
+		for _, kv := range expand(e) {
+			m[kv.Key] = append(m[kv.Key], kv.Value)
+		}
+	}
+
+	return m
+}
+
+// FromSliceWithDuplicatesStable creates a map in the same way as
+// FromSliceWithDuplicates and then sorts the slice of each key with the
+// tieBreak function, which reports whether a should come before b. The sort is
+// stable, so elements that tieBreak considers equivalent keep the order in which
+// they appear in the provided slice, making the result fully deterministic.
+func FromSliceWithDuplicatesStable[E any, K comparable](s []E, key func(e E) K, tieBreak func(a, b E) bool) map[K][]E {
+	m := FromSliceWithDuplicates(s, key)
+
+	for _, bucket := range m {
+		slices.SortStableFunc(bucket, compareFunc(tieBreak))
+	}
+
+	return m
+}
+
+// FromSliceWithDuplicatesSorted creates a map in the same way as
+// FromSliceWithDuplicates and then sorts the slice of each key with the less
+// function, which reports whether a should come before b. Elements that less
+// considers equivalent keep the order in which they appear in the provided
+// slice, as with sort.SliceStable. It behaves exactly like
+// FromSliceWithDuplicatesStable, under a name that describes the intent when
+// sorting is the goal rather than breaking ties.
+func FromSliceWithDuplicatesSorted[E any, K comparable](s []E, key func(e E) K, less func(a, b E) bool) map[K][]E {
+	return FromSliceWithDuplicatesStable(s, key, less)
+}
+
+// compareFunc converts a less function, which reports whether a is less than b,
+// into a comparison function suitable for the slices package.
+func compareFunc[E any](less func(a, b E) bool) func(a, b E) int {
+	return func(a, b E) int {
+		switch {
+		case less(a, b):
+			return -1
+		case less(b, a):
+			return 1
+		}
+
+		return 0
+	}
+}
+
+// FromSliceWithDuplicatesAlloc creates a map in the same way as
+// FromSliceWithDuplicates, except that the slice of each new key is obtained by
+// calling the newBucket function, which allows the backing arrays to come from a
+// pool or an arena. The returned slice is truncated to zero length before use,
+// so only its backing array matters, and elements are appended to it, which
+// only allocates once its capacity is exhausted.
+func FromSliceWithDuplicatesAlloc[E any, K comparable](s []E, key func(e E) K, newBucket func() []E) map[K][]E {
+	m := make(map[K][]E)
+
+	for _, e := range s {
+		k := key(e)
+
+		bucket, ok := m[k]
+		if !ok {
+			bucket = newBucket()[:0]
+		}
+
+		m[k] = append(bucket, e)
+	}
+
+	return m
+}
+
+// FromSliceWithAliases creates a map in the same way as
+// FromSliceWithDuplicates, except that each key returned by the key function is
+// first looked up in the aliases map and replaced by the canonical key it is
+// mapped to, if any. This groups the elements whose keys are known synonyms
+// under the same key. Keys that are absent from the aliases map are used as is.
+func FromSliceWithAliases[E any](s []E, key func(e E) string, aliases map[string]string) map[string][]E {
+	return FromSliceWithDuplicates(s, func(e E) string {
+		k := key(e)
+		if canonical, ok := aliases[k]; ok {
+			return canonical
+		}
+
+		return k
+	})
+}
+
+// FromSliceWithIndices creates a map in the same way as
+// FromSliceWithDuplicates, except that it stores the indices of the elements in
+// the provided slice rather than the elements themselves. The indices of each
+// key are in ascending order and can be used to refer back to the slice.
+func FromSliceWithIndices[E any, K comparable](s []E, key func(e E) K) map[K][]int {
+	m := make(map[K][]int)
+
+	for i, e := range s {
+		k := key(e)
+
+		m[k] = append(m[k], i)
+	}
+
+	return m
+}
+
+// FromSliceWithDuplicatesDedupFunc creates a map in the same way as
+// FromSliceWithDuplicates, except that an element is not appended to the slice
+// of its key if that slice already holds an element that the equal function
+// considers equal to it. This supports element types that are not comparable,
+// such as structs holding slices or maps. Each element is compared against
+// every element already kept for its key, so the cost is quadratic in the size
+// of each key's slice.
+func FromSliceWithDuplicatesDedupFunc[E any, K comparable](s []E, key func(e E) K, equal func(a, b E) bool) map[K][]E {
+	m := make(map[K][]E)
+
+	for _, e := range s {
+		k := key(e)
+
+		if slices.ContainsFunc(m[k], func(kept E) bool { return equal(kept, e) }) {
+			continue
+		}
+
+		m[k] = append(m[k], e)
+	}
+
+	return m
+}
+
+// FromSliceFiltered creates a map in the same way as FromSlice, except that only
+// the elements for which the keep function returns true are stored in the map.
+// The keep function is called first, so the key function is never called for
+// the elements that are left out.
+func FromSliceFiltered[E any, K comparable](s []E, key func(e E) K, keep func(e E) bool) map[K]E {
+	m := make(map[K]E)
+
+	for _, e := range s {
+		if keep(e) {
+			m[key(e)] = e
+		}
+	}
+
+	return m
+}
+
+// FromSliceFilterWithDropped creates a map in the same way as
+// FromSliceFiltered, except that the elements for which the keep function
+// returns false are also returned in a separate slice, in the order they appear
+// in the provided slice, which helps to understand what a filter leaves out.
+// The key function is not called for dropped elements.
+func FromSliceFilterWithDropped[E any, K comparable](s []E, key func(e E) K, keep func(e E) bool) (map[K]E, []E) {
+	m := make(map[K]E)
+	dropped := make([]E, 0)
+
+	for _, e := range s {
+		if !keep(e) {
+			dropped = append(dropped, e)
+			continue
+		}
+
+		m[key(e)] = e
+	}
+
+	return m, dropped
+}
+
+// FromSliceAdaptive creates a map in the same way as FromSlice as long as the
+// keys of the elements are unique, and in the same way as
+// FromSliceWithDuplicates otherwise. Exactly one of the two returned maps is
+// non-nil:
+//   - when every key is unique, unique holds the elements, groups is nil and
+//     hadDuplicates is false;
+//   - when at least two elements share a key, unique is nil, groups holds all of
+//     the elements grouped by key in the order they appear in the provided
+//     slice, and hadDuplicates is true.
+//
+// This avoids allocating a slice per key in the common case where duplicates
+// are not expected, while still not losing any element when they do occur.
+func FromSliceAdaptive[E any, K comparable](s []E, key func(e E) K) (unique map[K]E, groups map[K][]E, hadDuplicates bool) {
+	unique = make(map[K]E, len(s))
+
+	for i, e := range s {
+		k := key(e)
+
+		if _, ok := unique[k]; !ok {
+			unique[k] = e
+			continue
+		}
+
+		// The keys seen so far are unique, so each of them starts a group of
+		// its own and the key function doesn't need to be called again.
+		groups = make(map[K][]E, len(unique))
+		for uk, ue := range unique {
+			groups[uk] = []E{ue}
+		}
+
+		groups[k] = append(groups[k], e)
+		for _, next := range s[i+1:] {
+			nk := key(next)
+			groups[nk] = append(groups[nk], next)
+		}
+
+		return nil, groups, true
+	}
+
+	return unique, nil, false
+}
+
+// FromSliceWithDuplicatesTraced creates a map in the same way as
+// FromSliceWithDuplicates, except that the onKey function is called with the
+// provided context, the key and the element, once for each element after its
+// key has been computed. This lets callers emit spans or metrics for each
+// keyed element without having to wrap the key function. The grouping itself
+// is not affected by onKey, and the context is only passed along, never
+// checked for cancellation.
+func FromSliceWithDuplicatesTraced[E any, K comparable](ctx context.Context, s []E, key func(e E) K, onKey func(ctx context.Context, k K, e E)) map[K][]E {
+	m := make(map[K][]E)
+
+	for _, e := range s {
+		k := key(e)
+		onKey(ctx, k, e)
+
+		m[k] = append(m[k], e)
+	}
+
+	return m
+}
+
+// FromSliceReduce creates a map in the same way as FromSliceWithDuplicates,
+// except that the elements sharing a key are folded into a single value instead
+// of being kept in a slice. For each element, in the order they appear in the
+// provided slice, the reduce function is called with the value accumulated so
+// far for its key, or initial for the first element of a key, and the element,
+// and its result is stored for the key. This allows computing per-key sums,
+// maximums or concatenations in a single pass. The initial value is shared by
+// every key, so reduce should not modify it in place when V is a reference
+// type, such as a slice or a map.
+func FromSliceReduce[E any, K comparable, V any](s []E, key func(e E) K, initial V, reduce func(acc V, e E) V) map[K]V {
+	m := make(map[K]V)
+
+	for _, e := range s {
+		k := key(e)
+
+		acc, ok := m[k]
+		if !ok {
+			acc = initial
+		}
+
+		m[k] = reduce(acc, e)
+	}
+
+	return m
+}
+
+// FromSliceIndexed creates a map in the same way as FromSlice, except that the
+// key function also receives the zero-based index of each element in the
+// provided slice, which allows keys that depend on the position of elements,
+// such as row numbers. As with FromSlice, if several elements produce the same
+// key, the last of them is kept.
+func FromSliceIndexed[E any, K comparable](s []E, key func(i int, e E) K) map[K]E {
+	m := make(map[K]E, len(s))
+
+	for i, e := range s {
+		m[key(i, e)] = e
+	}
+
+	return m
+}
+
+// FromSliceWithDuplicatesGuarded creates a map in the same way as
+// FromSliceWithDuplicates, except that it fails as soon as the key function
+// returns more than maxKeys distinct keys, which guards against a key function
+// that returns an unexpectedly large number of keys, for example a unique key
+// for every element. In that case, the map built from the elements preceding
+// the one with the extra key is returned along with an error that wraps
+// ErrTooManyKeys and names that key and the index of its element.
+func FromSliceWithDuplicatesGuarded[E any, K comparable](s []E, key func(e E) K, maxKeys int) (map[K][]E, error) {
+	m := make(map[K][]E)
+
+	for i, e := range s {
+		k := key(e)
+
+		if _, ok := m[k]; !ok && len(m) >= maxKeys {
+			return m, fmt.Errorf("%w: key %v of element at index %d exceeds the limit of %d", ErrTooManyKeys, k, i, maxKeys)
+		}
+
+		m[k] = append(m[k], e)
+	}
+
+	return m, nil
+}
+
+// Partition creates two maps in the same way as FromSlice in a single pass over
+// the provided slice: the elements for which the pred function returns true
+// are stored in matched, and the others in rest, both keyed by the key
+// function. Both maps are non-nil, even when empty.
+func Partition[E any, K comparable](s []E, key func(e E) K, pred func(e E) bool) (matched map[K]E, rest map[K]E) {
+	matched = make(map[K]E)
+	rest = make(map[K]E)
+
+	for _, e := range s {
+		if pred(e) {
+			matched[key(e)] = e
+		} else {
+			rest[key(e)] = e
+		}
+	}
+
+	return matched, rest
+}
+
+// FromSliceUnique creates a map in the same way as FromSliceStrict, except that
+// elements that are equal to each other may share a key, since storing either
+// of them gives the same map. It only fails when the key function returns the
+// same key for two different elements, which distinguishes real key conflicts
+// from harmless repeats. In that case, the map built from the preceding
+// elements is returned along with an error that wraps ErrDuplicateKey and names
+// the key and both elements.
+//
+// Elements are compared with the == operator, so repeated elements that are not
+// equal to themselves, such as NaN floating-point values, are reported as
+// conflicts. When E is an interface type, elements sharing a key whose dynamic
+// values cannot be compared, such as slices, cause an error that wraps
+// ErrIncomparable, along with the map built from the preceding elements,
+// rather than a panic.
+func FromSliceUnique[E comparable, K comparable](s []E, key func(e E) K) (map[K]E, error) {
+	m := make(map[K]E)
+
+	for _, e := range s {
+		k := key(e)
+
+		if existing, ok := m[k]; ok {
+			equal, err := equalElements(existing, e)
+			if err != nil {
+				return m, fmt.Errorf("%w: elements %v and %v for key %v: %v", ErrIncomparable, existing, e, k, err)
+			}
+
+			if !equal {
+				return m, fmt.Errorf("%w %v for different elements %v and %v", ErrDuplicateKey, k, existing, e)
+			}
+		}
+
+		m[k] = e
+	}
+
+	return m, nil
+}
+
+// equalElements reports whether a and b are equal, turning the panic raised
+// when comparing interface values holding incomparable dynamic values into an
+// error.
+func equalElements[E comparable](a, b E) (equal bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	return a == b, nil
+}
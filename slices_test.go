@@ -1,6 +1,10 @@
 package mapify
 
 import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
 	"strconv"
 	"strings"
 	"testing"
@@ -330,6 +334,87 @@ func TestFromSlice_WithCustomKeyFn(t *testing.T) {
 	}
 }
 
+// TestFromSliceWithValue verifies that the FromSliceWithValue function stores
+// the derived value of each element under its key, with later elements
+// overwriting earlier ones on key collisions.
+func TestFromSliceWithValue(t *testing.T) {
+	testUserBobby := TestUser{id: 5, name: "bob"}
+
+	for _, tc := range []struct {
+		name     string
+		input    []*TestUser
+		expected map[string]int
+	}{
+		{
+			name:     "nil-input-slice",
+			input:    nil,
+			expected: map[string]int{},
+		},
+		{
+			name:  "unique-keys",
+			input: []*TestUser{&testUserBob, &testUserAlice, &testUserFred},
+			expected: map[string]int{
+				"bob":   1,
+				"alice": 2,
+				"fred":  3,
+			},
+		},
+		{
+			name:  "colliding-keys",
+			input: []*TestUser{&testUserBob, &testUserAlice, &testUserBobby},
+			expected: map[string]int{
+				"bob":   5,
+				"alice": 2,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result := FromSliceWithValue(tc.input, func(u *TestUser) string {
+				return u.name
+			}, func(u *TestUser) int {
+				return u.id
+			})
+			verifyResult(t, tc.expected, result)
+		})
+	}
+}
+
+// TestFromSliceSorted verifies that the FromSliceSorted function returns the
+// keys in ascending order along with their elements, keeping the last element
+// of colliding keys.
+func TestFromSliceSorted(t *testing.T) {
+	testUserBobby := TestUser{id: 1, name: "bobby"}
+
+	for _, tc := range []struct {
+		name             string
+		input            []*TestUser
+		expectedKeys     []int
+		expectedElements []*TestUser
+	}{
+		{
+			name:             "nil-input-slice",
+			input:            nil,
+			expectedKeys:     []int{},
+			expectedElements: []*TestUser{},
+		},
+		{
+			name:             "colliding-keys",
+			input:            []*TestUser{&testUserMarie, &testUserBob, &testUserAlice, &testUserBobby, &testUserFred},
+			expectedKeys:     []int{1, 2, 3, 4},
+			expectedElements: []*TestUser{&testUserBobby, &testUserAlice, &testUserFred, &testUserMarie},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			keys, elements := FromSliceSorted(tc.input, func(u *TestUser) int {
+				return u.id
+			})
+
+			verifySlice(t, tc.expectedKeys, keys)
+			verifySlice(t, tc.expectedElements, elements)
+		})
+	}
+}
+
 // verifyResult is a convenience function to verify that an expected map of keys
 // K to values V matches the actual map of the same type.
 func verifyResult[V, K comparable](t *testing.T, expected, actual map[K]V) {
@@ -424,6 +509,73 @@ func TestFromSliceWithDuplicates(t *testing.T) {
 	}
 }
 
+// TestGroupBy verifies that the GroupBy function groups the elements by key in
+// the same way as FromSliceWithDuplicates.
+func TestGroupBy(t *testing.T) {
+	key := func(u *TestUser) int {
+		if u == nil {
+			return 0
+		}
+
+		return u.id % 2
+	}
+
+	for _, tc := range []struct {
+		name     string
+		input    []*TestUser
+		expected map[int][]*TestUser
+	}{
+		{
+			name:     "nil-input-slice",
+			input:    nil,
+			expected: map[int][]*TestUser{},
+		},
+		{
+			name:  "with-nils",
+			input: []*TestUser{&testUserBob, nil, &testUserAlice, &testUserFred, &testUserMarie},
+			expected: map[int][]*TestUser{
+				0: {nil, &testUserAlice, &testUserMarie},
+				1: {&testUserBob, &testUserFred},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result := GroupBy(tc.input, key)
+			verifyResultDuplicates(t, tc.expected, result)
+			verifyResultDuplicates(t, FromSliceWithDuplicates(tc.input, key), result)
+		})
+	}
+}
+
+// TestFromSliceWithDuplicatesCap verifies that the FromSliceWithDuplicatesCap
+// function groups the elements in the same way as FromSliceWithDuplicates,
+// whatever the hint.
+func TestFromSliceWithDuplicatesCap(t *testing.T) {
+	input := make([]int, 1000)
+	for i := range input {
+		input[i] = i
+	}
+
+	key := func(e int) int { return e % 7 }
+	expected := FromSliceWithDuplicates(input, key)
+
+	for _, hint := range []int{-1, 0, 1, 10, 143, 10000} {
+		t.Run(strconv.Itoa(hint)+"-hint", func(t *testing.T) {
+			result := FromSliceWithDuplicatesCap(input, key, hint)
+
+			if len(result) != len(expected) {
+				t.Fatalf("expected %d keys but got %d", len(expected), len(result))
+			}
+
+			for k, v := range expected {
+				verifySlice(t, v, result[k])
+			}
+		})
+	}
+
+	verifyResultDuplicates(t, map[int][]int{}, FromSliceWithDuplicatesCap(nil, key, 10))
+}
+
 // verifyResultDuplicates is a convenience function to verify that an expected
 // map of keys K to values of slices of elements E matches an actual map of the
 // same type.
@@ -473,3 +625,1105 @@ func verifyResultDuplicates[E, K comparable](t *testing.T, expected, actual map[
 		}
 	}
 }
+
+// TestFromSliceValidated verifies that the FromSliceValidated function only
+// stores valid elements in the map and collects an error for each invalid
+// element.
+func TestFromSliceValidated(t *testing.T) {
+	errNoName := errors.New("user has no name")
+
+	testUserNameless := TestUser{id: 5}
+	testUserNamelessToo := TestUser{id: 6}
+
+	validate := func(u *TestUser) error {
+		if u.name == "" {
+			return errNoName
+		}
+
+		return nil
+	}
+
+	input := []*TestUser{
+		&testUserBob,
+		&testUserNameless,
+		&testUserAlice,
+		&testUserNamelessToo,
+	}
+
+	result, errs := FromSliceValidated(input, (*TestUser).ID, validate)
+
+	verifyResult(t, map[string]*TestUser{
+		"user-1": &testUserBob,
+		"user-2": &testUserAlice,
+	}, result)
+
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors but got %d: %v", len(errs), errs)
+	}
+
+	for i, expected := range []string{
+		"element at index 1: user has no name",
+		"element at index 3: user has no name",
+	} {
+		if !errors.Is(errs[i], errNoName) {
+			t.Logf("error %d is expected to wrap %v", i, errNoName)
+			t.Fail()
+		}
+
+		if errs[i].Error() != expected {
+			t.Logf("error %d is expected to be %q but was %q", i, expected, errs[i].Error())
+			t.Fail()
+		}
+	}
+}
+
+// TestFromSliceE verifies that the FromSliceE function builds the map until the
+// key function fails and reports the index of the failing element.
+func TestFromSliceE(t *testing.T) {
+	errMalformed := errors.New("malformed id")
+
+	key := func(s string) (int, error) {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return 0, errMalformed
+		}
+
+		return n, nil
+	}
+
+	for _, tc := range []struct {
+		name          string
+		input         []string
+		expected      map[int]string
+		expectedError error
+		expectedText  string
+	}{
+		{
+			name:     "nil-input-slice",
+			input:    nil,
+			expected: map[int]string{},
+		},
+		{
+			name:     "no-errors",
+			input:    []string{"1", "2", "3"},
+			expected: map[int]string{1: "1", 2: "2", 3: "3"},
+		},
+		{
+			name:          "error",
+			input:         []string{"1", "2", "x", "4"},
+			expected:      map[int]string{1: "1", 2: "2"},
+			expectedError: errMalformed,
+			expectedText:  "element at index 2: malformed id",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := FromSliceE(tc.input, key)
+			verifyResult(t, tc.expected, result)
+			verifyError(t, tc.expectedError, tc.expectedText, err)
+		})
+	}
+}
+
+// TestFromSliceStrict verifies that the FromSliceStrict function builds the
+// same map as FromSlice when keys are unique and fails on the first collision.
+func TestFromSliceStrict(t *testing.T) {
+	testUserBobby := TestUser{id: 1, name: "bobby"}
+
+	for _, tc := range []struct {
+		name          string
+		input         []*TestUser
+		expected      map[string]*TestUser
+		expectedError error
+		expectedText  string
+	}{
+		{
+			name:     "nil-input-slice",
+			input:    nil,
+			expected: map[string]*TestUser{},
+		},
+		{
+			name:  "unique-keys",
+			input: []*TestUser{&testUserBob, &testUserAlice},
+			expected: map[string]*TestUser{
+				"user-1": &testUserBob,
+				"user-2": &testUserAlice,
+			},
+		},
+		{
+			name:  "colliding-keys",
+			input: []*TestUser{&testUserBob, &testUserAlice, &testUserBobby, &testUserFred},
+			expected: map[string]*TestUser{
+				"user-1": &testUserBob,
+				"user-2": &testUserAlice,
+			},
+			expectedError: ErrDuplicateKey,
+			expectedText:  "duplicate key user-1 at indices 0 and 2",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := FromSliceStrict(tc.input, (*TestUser).ID)
+			verifyResult(t, tc.expected, result)
+			verifyError(t, tc.expectedError, tc.expectedText, err)
+		})
+	}
+}
+
+// TestFromSliceContext verifies that the FromSliceContext function builds the
+// whole map when its context is not cancelled and stops early, returning the
+// partial map and the context's error, when it is.
+func TestFromSliceContext(t *testing.T) {
+	input := make([]int, 5*contextCheckInterval)
+	for i := range input {
+		input[i] = i
+	}
+
+	result, err := FromSliceContext(context.Background(), input, func(e int) int { return e })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result) != len(input) {
+		t.Fatalf("expected %d keys but got %d", len(input), len(result))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	result, err = FromSliceContext(ctx, input, func(e int) int {
+		if e == contextCheckInterval+10 {
+			cancel()
+		}
+
+		return e
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected %v but got %v", context.Canceled, err)
+	}
+
+	if len(result) != 2*contextCheckInterval {
+		t.Fatalf("expected %d keys but got %d", 2*contextCheckInterval, len(result))
+	}
+
+	result, err = FromSliceContext(ctx, input, func(e int) int { return e })
+	if !errors.Is(err, context.Canceled) || len(result) != 0 {
+		t.Fatalf("expected an empty map and %v but got %d keys and %v", context.Canceled, len(result), err)
+	}
+}
+
+// TestFromSliceMapE verifies that the FromSliceMapE function builds a map of
+// derived keys and values and stops at the first error returned by either the
+// key or the value function.
+func TestFromSliceMapE(t *testing.T) {
+	errKey := errors.New("bad key")
+	errValue := errors.New("bad value")
+
+	input := []*TestUser{&testUserBob, &testUserAlice, &testUserFred}
+
+	nameKey := func(u *TestUser) (string, error) {
+		return u.name, nil
+	}
+	idValue := func(u *TestUser) (int, error) {
+		return u.id, nil
+	}
+	failOn := func(name string, err error) func(u *TestUser) (int, error) {
+		return func(u *TestUser) (int, error) {
+			if u.name == name {
+				return 0, err
+			}
+
+			return u.id, nil
+		}
+	}
+
+	for _, tc := range []struct {
+		name          string
+		key           func(*TestUser) (string, error)
+		value         func(*TestUser) (int, error)
+		expected      map[string]int
+		expectedError error
+		expectedText  string
+	}{
+		{
+			name:     "no-errors",
+			key:      nameKey,
+			value:    idValue,
+			expected: map[string]int{"bob": 1, "alice": 2, "fred": 3},
+		},
+		{
+			name: "key-error",
+			key: func(u *TestUser) (string, error) {
+				if u.name == "alice" {
+					return "", errKey
+				}
+
+				return u.name, nil
+			},
+			value: func(u *TestUser) (int, error) {
+				if u.name == "alice" {
+					t.Log("value function is not expected to be called when the key function fails")
+					t.Fail()
+				}
+
+				return u.id, nil
+			},
+			expected:      map[string]int{"bob": 1},
+			expectedError: errKey,
+			expectedText:  "element at index 1: key: bad key",
+		},
+		{
+			name:          "value-error",
+			key:           nameKey,
+			value:         failOn("fred", errValue),
+			expected:      map[string]int{"bob": 1, "alice": 2},
+			expectedError: errValue,
+			expectedText:  "element at index 2: value: bad value",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := FromSliceMapE(input, tc.key, tc.value)
+			verifyResult(t, tc.expected, result)
+			verifyError(t, tc.expectedError, tc.expectedText, err)
+		})
+	}
+}
+
+// verifyError is a convenience function to verify that an actual error wraps
+// the expected error and has the expected text. If the expected error is nil,
+// the actual error is expected to be nil as well.
+func verifyError(t *testing.T, expected error, expectedText string, actual error) {
+	if expected == nil {
+		if actual != nil {
+			t.Logf("actual is expected to be nil but was %v", actual)
+			t.Fail()
+		}
+
+		return
+	}
+
+	if !errors.Is(actual, expected) {
+		t.Logf("actual is expected to wrap %v but was %v", expected, actual)
+		t.Fail()
+	} else if actual.Error() != expectedText {
+		t.Logf("actual is expected to be %q but was %q", expectedText, actual.Error())
+		t.Fail()
+	}
+}
+
+// TestFromSliceWeightedMultiKey verifies that the FromSliceWeightedMultiKey
+// function stores each element under every key returned by the keyed function
+// with the corresponding weight.
+func TestFromSliceWeightedMultiKey(t *testing.T) {
+	type weighted = struct {
+		Elem   *TestUser
+		Weight float64
+	}
+
+	keyed := func(u *TestUser) map[string]float64 {
+		switch u {
+		case &testUserBob:
+			return map[string]float64{"boys": 0.75, "girls": 0.25}
+		case &testUserAlice:
+			return map[string]float64{"girls": 1}
+		}
+
+		return nil
+	}
+
+	result := FromSliceWeightedMultiKey([]*TestUser{&testUserBob, &testUserAlice, &testUserFred}, keyed)
+
+	verifyResultDuplicates(t, map[string][]weighted{
+		"boys":  {{Elem: &testUserBob, Weight: 0.75}},
+		"girls": {{Elem: &testUserBob, Weight: 0.25}, {Elem: &testUserAlice, Weight: 1}},
+	}, result)
+}
+
+// TestFromSliceWithDuplicatesE verifies that the FromSliceWithDuplicatesE
+// function groups the elements until the key function fails and returns the
+// partial grouping along with the error.
+func TestFromSliceWithDuplicatesE(t *testing.T) {
+	errOdd := errors.New("odd id")
+
+	key := func(u *TestUser) (int, error) {
+		if u.id == 3 {
+			return 0, errOdd
+		}
+
+		return u.id % 2, nil
+	}
+
+	for _, tc := range []struct {
+		name          string
+		input         []*TestUser
+		expected      map[int][]*TestUser
+		expectedError error
+		expectedText  string
+	}{
+		{
+			name:  "no-errors",
+			input: []*TestUser{&testUserBob, &testUserAlice, &testUserMarie},
+			expected: map[int][]*TestUser{
+				0: {&testUserAlice, &testUserMarie},
+				1: {&testUserBob},
+			},
+		},
+		{
+			name:  "error-at-second-element",
+			input: []*TestUser{&testUserAlice, &testUserFred, &testUserMarie},
+			expected: map[int][]*TestUser{
+				0: {&testUserAlice},
+			},
+			expectedError: errOdd,
+			expectedText:  "element at index 1: odd id",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := FromSliceWithDuplicatesE(tc.input, key)
+			verifyResultDuplicates(t, tc.expected, result)
+			verifyError(t, tc.expectedError, tc.expectedText, err)
+		})
+	}
+}
+
+// TestFromSliceWithDuplicatesCollectErrors verifies that the
+// FromSliceWithDuplicatesCollectErrors function groups every element for which
+// the key function succeeds and returns an error for each one that fails.
+func TestFromSliceWithDuplicatesCollectErrors(t *testing.T) {
+	errNoName := errors.New("user has no name")
+
+	testUserNameless := TestUser{id: 5}
+
+	key := func(u *TestUser) (int, error) {
+		if u.name == "" {
+			return 0, errNoName
+		}
+
+		return u.id % 2, nil
+	}
+
+	input := []*TestUser{
+		&testUserBob,
+		&testUserNameless,
+		&testUserAlice,
+		&testUserFred,
+		&testUserNameless,
+	}
+
+	result, errs := FromSliceWithDuplicatesCollectErrors(input, key)
+
+	verifyResultDuplicates(t, map[int][]*TestUser{
+		0: {&testUserAlice},
+		1: {&testUserBob, &testUserFred},
+	}, result)
+
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors but got %d: %v", len(errs), errs)
+	}
+
+	verifyError(t, errNoName, "element at index 1: user has no name", errs[0])
+	verifyError(t, errNoName, "element at index 4: user has no name", errs[1])
+}
+
+// TestFlatMap verifies that the FlatMap function appends the value of every
+// pair returned by the expand function to the slice of its key.
+func TestFlatMap(t *testing.T) {
+	type pair = struct {
+		Key   string
+		Value int
+	}
+
+	expand := func(g *TestGroup) []pair {
+		var pairs []pair
+
+		for _, member := range g.members {
+			u := member.(*TestUser)
+			pairs = append(pairs, pair{Key: u.name, Value: g.id}, pair{Key: g.name, Value: u.id})
+		}
+
+		return pairs
+	}
+
+	testGroupEmpty := TestGroup{id: 1003, name: "empty"}
+
+	result := FlatMap([]*TestGroup{&testGroupBoys, &testGroupEmpty, &testGroupGirls}, expand)
+
+	if _, ok := result["empty"]; ok {
+		t.Log("actual is not expected to contain the key empty")
+		t.Fail()
+	}
+
+	if len(result) != 6 {
+		t.Fatalf("expected 6 keys but got %d: %v", len(result), result)
+	}
+
+	verifySlice(t, []int{1, 3}, result["boys"])
+	verifySlice(t, []int{2, 4}, result["girls"])
+	verifySlice(t, []int{1001}, result["bob"])
+	verifySlice(t, []int{1001}, result["fred"])
+	verifySlice(t, []int{1002}, result["alice"])
+	verifySlice(t, []int{1002}, result["marie"])
+}
+
+// TestFromSliceWithDuplicatesStable verifies that the
+// FromSliceWithDuplicatesStable function sorts the slice of each key with the
+// tie-breaker and keeps equivalent elements in their original order.
+func TestFromSliceWithDuplicatesStable(t *testing.T) {
+	testUserAlanna := TestUser{id: 2, name: "alanna"}
+	testUserAnnie := TestUser{id: 2, name: "annie"}
+	testUserBen := TestUser{id: 1, name: "ben"}
+
+	input := []*TestUser{
+		&testUserMarie,
+		&testUserAnnie,
+		&testUserBob,
+		&testUserAlice,
+		&testUserBen,
+		&testUserAlanna,
+	}
+
+	result := FromSliceWithDuplicatesStable(input, func(u *TestUser) byte {
+		return u.name[0]
+	}, func(a, b *TestUser) bool {
+		return a.id < b.id
+	})
+
+	if len(result) != 3 {
+		t.Fatalf("expected 3 keys but got %d", len(result))
+	}
+
+	verifySlice(t, []*TestUser{&testUserAnnie, &testUserAlice, &testUserAlanna}, result['a'])
+	verifySlice(t, []*TestUser{&testUserBob, &testUserBen}, result['b'])
+	verifySlice(t, []*TestUser{&testUserMarie}, result['m'])
+}
+
+// TestFromSliceWithDuplicatesAlloc verifies that the
+// FromSliceWithDuplicatesAlloc function obtains one slice per key from the
+// allocator and accumulates the elements correctly regardless of its capacity.
+func TestFromSliceWithDuplicatesAlloc(t *testing.T) {
+	input := []int{1, 2, 3, 4, 5, 6, 7, 8, 9}
+	key := func(e int) int { return e % 3 }
+
+	for _, capacity := range []int{0, 1, 8} {
+		t.Run(strconv.Itoa(capacity)+"-capacity", func(t *testing.T) {
+			allocations := 0
+
+			result := FromSliceWithDuplicatesAlloc(input, key, func() []int {
+				allocations++
+				return make([]int, 1, capacity+1)
+			})
+
+			if allocations != 3 {
+				t.Fatalf("expected 3 allocations but got %d", allocations)
+			}
+
+			if len(result) != 3 {
+				t.Fatalf("expected 3 keys but got %v", result)
+			}
+
+			verifySlice(t, []int{3, 6, 9}, result[0])
+			verifySlice(t, []int{1, 4, 7}, result[1])
+			verifySlice(t, []int{2, 5, 8}, result[2])
+		})
+	}
+}
+
+// TestFromSliceWithAliases verifies that the FromSliceWithAliases function
+// groups the elements with aliased keys under their canonical key and keeps the
+// other keys separate.
+func TestFromSliceWithAliases(t *testing.T) {
+	type Item struct {
+		country string
+		name    string
+	}
+
+	input := []Item{
+		{country: "USA", name: "a"},
+		{country: "United States", name: "b"},
+		{country: "Canada", name: "c"},
+		{country: "US", name: "d"},
+		{country: "UK", name: "e"},
+	}
+
+	aliases := map[string]string{
+		"USA":           "US",
+		"United States": "US",
+	}
+
+	result := FromSliceWithAliases(input, func(i Item) string {
+		return i.country
+	}, aliases)
+
+	names := make(map[string][]string, len(result))
+	for k, v := range result {
+		for _, i := range v {
+			names[k] = append(names[k], i.name)
+		}
+	}
+
+	if len(names) != 3 {
+		t.Fatalf("expected 3 keys but got %v", names)
+	}
+
+	verifySlice(t, []string{"a", "b", "d"}, names["US"])
+	verifySlice(t, []string{"c"}, names["Canada"])
+	verifySlice(t, []string{"e"}, names["UK"])
+}
+
+// TestFromSliceWithIndices verifies that the FromSliceWithIndices function maps
+// each key to the ascending indices of its elements.
+func TestFromSliceWithIndices(t *testing.T) {
+	input := []string{"apple", "banana", "avocado", "cherry", "blueberry", "apricot"}
+
+	result := FromSliceWithIndices(input, func(s string) byte {
+		return s[0]
+	})
+
+	if len(result) != 3 {
+		t.Fatalf("expected 3 keys but got %d", len(result))
+	}
+
+	verifySlice(t, []int{0, 2, 5}, result['a'])
+	verifySlice(t, []int{1, 4}, result['b'])
+	verifySlice(t, []int{3}, result['c'])
+}
+
+// TestFromSliceWithDuplicatesDedupFunc verifies that the
+// FromSliceWithDuplicatesDedupFunc function only keeps the first of the
+// elements of a key that are equal according to the custom equality function.
+func TestFromSliceWithDuplicatesDedupFunc(t *testing.T) {
+	type Tagged struct {
+		name string
+		tags []string
+	}
+
+	input := []Tagged{
+		{name: "bob", tags: []string{"admin"}},
+		{name: "bob", tags: []string{"user"}},
+		{name: "alice", tags: []string{"user"}},
+		{name: "bob", tags: []string{"admin", "owner"}},
+		{name: "alice", tags: []string{"admin"}},
+	}
+
+	result := FromSliceWithDuplicatesDedupFunc(input, func(e Tagged) string {
+		return e.tags[0]
+	}, func(a, b Tagged) bool {
+		return a.name == b.name
+	})
+
+	names := make(map[string][]string, len(result))
+	for k, v := range result {
+		for _, e := range v {
+			names[k] = append(names[k], e.name)
+		}
+	}
+
+	if len(names) != 2 {
+		t.Fatalf("expected 2 keys but got %v", names)
+	}
+
+	verifySlice(t, []string{"bob", "alice"}, names["admin"])
+	verifySlice(t, []string{"bob", "alice"}, names["user"])
+	verifySlice(t, []string{"admin"}, result["admin"][0].tags)
+}
+
+// TestFromSliceFiltered verifies that the FromSliceFiltered function only
+// stores the elements that satisfy the predicate and never computes the key of
+// the others.
+func TestFromSliceFiltered(t *testing.T) {
+	input := []*TestUser{&testUserBob, nil, &testUserAlice, &testUserFred, nil, &testUserMarie}
+
+	result := FromSliceFiltered(input, func(u *TestUser) int {
+		return u.id
+	}, func(u *TestUser) bool {
+		return u != nil && u.id != 3
+	})
+
+	verifyResult(t, map[int]*TestUser{
+		1: &testUserBob,
+		2: &testUserAlice,
+		4: &testUserMarie,
+	}, result)
+}
+
+// TestFromSliceFilterWithDropped verifies that the FromSliceFilterWithDropped
+// function partitions the input into the kept elements, stored in the map, and
+// the dropped elements, returned in input order.
+func TestFromSliceFilterWithDropped(t *testing.T) {
+	input := []*TestUser{&testUserBob, &testUserAlice, &testUserFred, &testUserMarie}
+
+	result, dropped := FromSliceFilterWithDropped(input, (*TestUser).ID, func(u *TestUser) bool {
+		return u.id%2 == 0
+	})
+
+	verifyResult(t, map[string]*TestUser{
+		"user-2": &testUserAlice,
+		"user-4": &testUserMarie,
+	}, result)
+	verifySlice(t, []*TestUser{&testUserBob, &testUserFred}, dropped)
+
+	if len(result)+len(dropped) != len(input) {
+		t.Fatalf("kept and dropped elements are expected to add up to %d but got %d", len(input), len(result)+len(dropped))
+	}
+}
+
+// TestFromSliceAdaptive verifies that the FromSliceAdaptive function returns
+// the ungrouped map when all keys are unique and the grouped map as soon as a
+// key is shared by several elements.
+func TestFromSliceAdaptive(t *testing.T) {
+	t.Run("unique-keys", func(t *testing.T) {
+		input := []*TestUser{&testUserBob, &testUserAlice, &testUserFred, &testUserMarie}
+
+		unique, groups, hadDuplicates := FromSliceAdaptive(input, (*TestUser).ID)
+
+		if hadDuplicates || groups != nil {
+			t.Fatalf("expected no duplicates but got hadDuplicates=%t and groups %v", hadDuplicates, groups)
+		}
+
+		verifyResult(t, map[string]*TestUser{
+			"user-1": &testUserBob,
+			"user-2": &testUserAlice,
+			"user-3": &testUserFred,
+			"user-4": &testUserMarie,
+		}, unique)
+	})
+
+	t.Run("colliding-keys", func(t *testing.T) {
+		input := []int{1, 2, 3, 4, 5, 6, 7}
+
+		unique, groups, hadDuplicates := FromSliceAdaptive(input, func(e int) int {
+			return e % 3
+		})
+
+		if !hadDuplicates || unique != nil {
+			t.Fatalf("expected duplicates but got hadDuplicates=%t and unique %v", hadDuplicates, unique)
+		}
+
+		if len(groups) != 3 {
+			t.Fatalf("expected 3 groups but got %d", len(groups))
+		}
+
+		verifySlice(t, []int{3, 6}, groups[0])
+		verifySlice(t, []int{1, 4, 7}, groups[1])
+		verifySlice(t, []int{2, 5}, groups[2])
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		unique, groups, hadDuplicates := FromSliceAdaptive(nil, (*TestUser).ID)
+
+		if unique == nil || len(unique) != 0 || groups != nil || hadDuplicates {
+			t.Fatalf("expected an empty unique map but got %v, %v, %t", unique, groups, hadDuplicates)
+		}
+	})
+}
+
+// TestFromSliceWithDuplicatesTraced verifies that the
+// FromSliceWithDuplicatesTraced function calls onKey once per element, in
+// order, with the provided context and the key of the element, and groups the
+// elements like FromSliceWithDuplicates.
+func TestFromSliceWithDuplicatesTraced(t *testing.T) {
+	type ctxKey struct{}
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "trace-id")
+	input := []int{1, 2, 3, 4, 5, 6, 7}
+	key := func(e int) int { return e % 3 }
+
+	var keys, elements []int
+
+	result := FromSliceWithDuplicatesTraced(ctx, input, key, func(ctx context.Context, k int, e int) {
+		if v := ctx.Value(ctxKey{}); v != "trace-id" {
+			t.Logf("expected the provided context but got a context holding %v", v)
+			t.Fail()
+		}
+
+		keys = append(keys, k)
+		elements = append(elements, e)
+	})
+
+	verifySlice(t, input, elements)
+	verifySlice(t, []int{1, 2, 0, 1, 2, 0, 1}, keys)
+
+	expected := FromSliceWithDuplicates(input, key)
+	if len(result) != len(expected) {
+		t.Fatalf("expected %d keys but got %d", len(expected), len(result))
+	}
+
+	for k, v := range expected {
+		verifySlice(t, v, result[k])
+	}
+}
+
+// TestFromSliceReduce verifies that the FromSliceReduce function folds the
+// elements of each key, in order, starting from the initial value.
+func TestFromSliceReduce(t *testing.T) {
+	input := []int{1, 2, 3, 4, 5, 6, 7}
+	key := func(e int) int { return e % 3 }
+	maxInt := func(acc, e int) int { return max(acc, e) }
+
+	verifyResult(t, map[int]int{0: 9, 1: 12, 2: 7}, FromSliceReduce(input, key, 0, func(acc, e int) int {
+		return acc + e
+	}))
+
+	verifyResult(t, map[int]int{0: 6, 1: 7, 2: 5}, FromSliceReduce(input, key, 0, maxInt))
+
+	verifyResult(t, map[int]string{0: "x36", 1: "x147", 2: "x25"}, FromSliceReduce(input, key, "x", func(acc string, e int) string {
+		return acc + strconv.Itoa(e)
+	}))
+
+	verifyResult(t, map[int]int{}, FromSliceReduce(nil, key, 0, maxInt))
+}
+
+// TestFromSliceIndexed verifies that the FromSliceIndexed function passes the
+// index of each element to the key function and keeps the last element of a
+// repeated key.
+func TestFromSliceIndexed(t *testing.T) {
+	input := []*TestUser{&testUserBob, &testUserAlice, &testUserFred, &testUserMarie}
+
+	verifyResult(t, map[int]*TestUser{
+		0: &testUserBob,
+		1: &testUserAlice,
+		2: &testUserFred,
+		3: &testUserMarie,
+	}, FromSliceIndexed(input, func(i int, _ *TestUser) int {
+		return i
+	}))
+
+	verifyResult(t, map[string]*TestUser{
+		"0-user-1": &testUserBob,
+		"1-user-2": &testUserAlice,
+		"0-user-3": &testUserFred,
+		"1-user-4": &testUserMarie,
+	}, FromSliceIndexed(input, func(i int, u *TestUser) string {
+		return strconv.Itoa(i%2) + "-" + u.ID()
+	}))
+
+	verifyResult(t, map[bool]*TestUser{
+		true:  &testUserFred,
+		false: &testUserMarie,
+	}, FromSliceIndexed(input, func(i int, _ *TestUser) bool {
+		return i%2 == 0
+	}))
+
+	verifyResult(t, map[int]*TestUser{}, FromSliceIndexed(nil, func(i int, _ *TestUser) int {
+		return i
+	}))
+}
+
+// TestFromSliceWithDuplicatesGuarded verifies that the
+// FromSliceWithDuplicatesGuarded function groups the elements when the number
+// of distinct keys stays within the limit, and otherwise returns the partial
+// grouping along with an error wrapping ErrTooManyKeys.
+func TestFromSliceWithDuplicatesGuarded(t *testing.T) {
+	input := []int{1, 2, 3, 4, 5, 6, 7}
+	key := func(e int) int { return e % 3 }
+
+	for _, tc := range []struct {
+		name         string
+		maxKeys      int
+		expected     map[int][]int
+		expectedErr  error
+		expectedText string
+	}{
+		{
+			name:     "under-the-limit",
+			maxKeys:  4,
+			expected: map[int][]int{0: {3, 6}, 1: {1, 4, 7}, 2: {2, 5}},
+		},
+		{
+			name:     "at-the-limit",
+			maxKeys:  3,
+			expected: map[int][]int{0: {3, 6}, 1: {1, 4, 7}, 2: {2, 5}},
+		},
+		{
+			name:         "over-the-limit",
+			maxKeys:      2,
+			expected:     map[int][]int{1: {1}, 2: {2}},
+			expectedErr:  ErrTooManyKeys,
+			expectedText: "too many distinct keys: key 0 of element at index 2 exceeds the limit of 2",
+		},
+		{
+			name:         "no-keys-allowed",
+			maxKeys:      0,
+			expected:     map[int][]int{},
+			expectedErr:  ErrTooManyKeys,
+			expectedText: "too many distinct keys: key 1 of element at index 0 exceeds the limit of 0",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := FromSliceWithDuplicatesGuarded(input, key, tc.maxKeys)
+
+			verifyError(t, tc.expectedErr, tc.expectedText, err)
+
+			if len(result) != len(tc.expected) {
+				t.Fatalf("expected %d keys but got %d", len(tc.expected), len(result))
+			}
+
+			for k, v := range tc.expected {
+				verifySlice(t, v, result[k])
+			}
+		})
+	}
+}
+
+// TestFromSliceWithArray verifies that arrays, and pointers to arrays, are
+// mapified by slicing them, which neither copies nor modifies the array.
+func TestFromSliceWithArray(t *testing.T) {
+	input := [4]*TestUser{&testUserBob, &testUserAlice, &testUserFred, &testUserMarie}
+	expected := map[string]*TestUser{
+		"user-1": &testUserBob,
+		"user-2": &testUserAlice,
+		"user-3": &testUserFred,
+		"user-4": &testUserMarie,
+	}
+
+	verifyResult(t, expected, FromSlice(input[:], (*TestUser).ID))
+
+	pointer := &input
+	verifyResult(t, expected, FromSlice(pointer[:], (*TestUser).ID))
+
+	groups := FromSliceWithDuplicates(input[1:3], func(u *TestUser) bool {
+		return u.id%2 == 0
+	})
+
+	verifySlice(t, []*TestUser{&testUserAlice}, groups[true])
+	verifySlice(t, []*TestUser{&testUserFred}, groups[false])
+
+	var empty [0]*TestUser
+	verifyResult(t, map[string]*TestUser{}, FromSlice(empty[:], (*TestUser).ID))
+}
+
+// TestPartition verifies that the Partition function stores each element in
+// either the matched or the rest map, depending on the predicate, and always
+// returns non-nil maps.
+func TestPartition(t *testing.T) {
+	input := []*TestUser{&testUserBob, &testUserAlice, &testUserFred, &testUserMarie}
+
+	for _, tc := range []struct {
+		name            string
+		pred            func(u *TestUser) bool
+		expectedMatched map[string]*TestUser
+		expectedRest    map[string]*TestUser
+	}{
+		{
+			name: "some-match",
+			pred: func(u *TestUser) bool { return u.id%2 == 0 },
+			expectedMatched: map[string]*TestUser{
+				"user-2": &testUserAlice,
+				"user-4": &testUserMarie,
+			},
+			expectedRest: map[string]*TestUser{
+				"user-1": &testUserBob,
+				"user-3": &testUserFred,
+			},
+		},
+		{
+			name: "all-match",
+			pred: func(u *TestUser) bool { return true },
+			expectedMatched: map[string]*TestUser{
+				"user-1": &testUserBob,
+				"user-2": &testUserAlice,
+				"user-3": &testUserFred,
+				"user-4": &testUserMarie,
+			},
+			expectedRest: map[string]*TestUser{},
+		},
+		{
+			name:            "none-match",
+			pred:            func(u *TestUser) bool { return false },
+			expectedMatched: map[string]*TestUser{},
+			expectedRest: map[string]*TestUser{
+				"user-1": &testUserBob,
+				"user-2": &testUserAlice,
+				"user-3": &testUserFred,
+				"user-4": &testUserMarie,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			matched, rest := Partition(input, (*TestUser).ID, tc.pred)
+
+			verifyResult(t, tc.expectedMatched, matched)
+			verifyResult(t, tc.expectedRest, rest)
+		})
+	}
+
+	matched, rest := Partition(nil, (*TestUser).ID, func(u *TestUser) bool { return true })
+	verifyResult(t, map[string]*TestUser{}, matched)
+	verifyResult(t, map[string]*TestUser{}, rest)
+}
+
+// TestFromSliceWithDuplicatesSorted verifies that the
+// FromSliceWithDuplicatesSorted function sorts the slice of each key and keeps
+// equivalent elements in the order they appear in the provided slice.
+func TestFromSliceWithDuplicatesSorted(t *testing.T) {
+	type score struct {
+		player string
+		team   string
+		points int
+	}
+
+	input := []score{
+		{"ann", "red", 7},
+		{"bob", "blue", 3},
+		{"cid", "red", 2},
+		{"dee", "red", 7},
+		{"eve", "blue", 1},
+		{"fay", "red", 5},
+	}
+
+	result := FromSliceWithDuplicatesSorted(input, func(s score) string {
+		return s.team
+	}, func(a, b score) bool {
+		return a.points > b.points
+	})
+
+	if len(result) != 2 {
+		t.Fatalf("expected 2 keys but got %d", len(result))
+	}
+
+	verifySlice(t, []score{input[0], input[3], input[5], input[2]}, result["red"])
+	verifySlice(t, []score{input[1], input[4]}, result["blue"])
+}
+
+// TestFromSliceUnique verifies that the FromSliceUnique function tolerates
+// equal elements sharing a key but fails when different elements do, returning
+// the partial map along with an error wrapping ErrDuplicateKey.
+func TestFromSliceUnique(t *testing.T) {
+	key := func(u TestUser) int { return u.id }
+
+	for _, tc := range []struct {
+		name         string
+		input        []TestUser
+		expected     map[int]TestUser
+		expectedErr  error
+		expectedText string
+	}{
+		{
+			name:     "empty",
+			input:    nil,
+			expected: map[int]TestUser{},
+		},
+		{
+			name:  "unique-keys",
+			input: []TestUser{testUserBob, testUserAlice, testUserFred},
+			expected: map[int]TestUser{
+				1: testUserBob,
+				2: testUserAlice,
+				3: testUserFred,
+			},
+		},
+		{
+			name:  "repeated-elements",
+			input: []TestUser{testUserBob, testUserAlice, testUserBob, testUserAlice},
+			expected: map[int]TestUser{
+				1: testUserBob,
+				2: testUserAlice,
+			},
+		},
+		{
+			name:  "conflicting-elements",
+			input: []TestUser{testUserBob, testUserAlice, testUserBob, {id: 2, name: "alanna"}, testUserFred},
+			expected: map[int]TestUser{
+				1: testUserBob,
+				2: testUserAlice,
+			},
+			expectedErr:  ErrDuplicateKey,
+			expectedText: "duplicate key 2 for different elements {2 alice} and {2 alanna}",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := FromSliceUnique(tc.input, key)
+
+			verifyError(t, tc.expectedErr, tc.expectedText, err)
+			verifyResult(t, tc.expected, result)
+		})
+	}
+}
+
+// TestFromSliceUnique_IncomparableAndNaN verifies that the FromSliceUnique
+// function returns an error wrapping ErrIncomparable, rather than panicking,
+// when interface elements sharing a key hold incomparable values, and that it
+// reports repeated NaN elements as a conflict.
+func TestFromSliceUnique_IncomparableAndNaN(t *testing.T) {
+	constant := func(any) int { return 0 }
+
+	result, err := FromSliceUnique([]any{[]int{1}, []int{1}}, constant)
+	if !errors.Is(err, ErrIncomparable) {
+		t.Fatalf("expected an error wrapping %v but got %v", ErrIncomparable, err)
+	}
+
+	if len(result) != 1 {
+		t.Logf("expected the partial map to hold 1 element but got %v", result)
+		t.Fail()
+	}
+
+	if _, err := FromSliceUnique([]any{[]int{1}, "one"}, constant); !errors.Is(err, ErrDuplicateKey) {
+		t.Logf("expected values of different dynamic types to conflict but got %v", err)
+		t.Fail()
+	}
+
+	nan := math.NaN()
+	if _, err := FromSliceUnique([]float64{nan, nan}, func(float64) int { return 0 }); !errors.Is(err, ErrDuplicateKey) {
+		t.Logf("expected repeated NaN elements to conflict but got %v", err)
+		t.Fail()
+	}
+}
+
+// BenchmarkFromSlice measures FromSlice on a large slice with unique keys, which
+// is dominated by the cost of growing the map.
+func BenchmarkFromSlice(b *testing.B) {
+	input := make([]int, 1000000)
+	for i := range input {
+		input[i] = i
+	}
+
+	key := func(e int) int { return e }
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		FromSlice(input, key)
+	}
+}
+
+// skewedInput is a slice of keys following a Zipf distribution, so that a few
+// keys are very frequent while most keys are rare, used by the grouping
+// benchmarks.
+var skewedInput = func() []int {
+	zipf := rand.NewZipf(rand.New(rand.NewSource(1)), 1.1, 1, 9999)
+
+	s := make([]int, 1000000)
+	for i := range s {
+		s[i] = int(zipf.Uint64())
+	}
+
+	return s
+}()
+
+// BenchmarkFromSliceWithDuplicates_Skewed measures FromSliceWithDuplicates on
+// a skewed key distribution, as a baseline for
+// BenchmarkFromSliceWithDuplicatesCap_Skewed.
+func BenchmarkFromSliceWithDuplicates_Skewed(b *testing.B) {
+	key := func(e int) int { return e }
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		FromSliceWithDuplicates(skewedInput, key)
+	}
+}
+
+// BenchmarkFromSliceWithDuplicatesCap_Skewed measures
+// FromSliceWithDuplicatesCap on a skewed key distribution with various hints.
+func BenchmarkFromSliceWithDuplicatesCap_Skewed(b *testing.B) {
+	key := func(e int) int { return e }
+
+	for _, hint := range []int{16, 64, 256} {
+		b.Run(strconv.Itoa(hint)+"-hint", func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				FromSliceWithDuplicatesCap(skewedInput, key, hint)
+			}
+		})
+	}
+}
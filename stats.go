@@ -0,0 +1,241 @@
+package mapify
+
+import (
+	"cmp"
+	"math"
+)
+
+// Count creates a map of keys K to the number of elements of the provided slice
+// that have that key, as determined by the key function. Unlike
+// FromSliceWithDuplicates, it does not keep the elements themselves, which makes
+// it cheaper when only the counts are needed.
+func Count[E any, K comparable](s []E, key func(e E) K) map[K]int {
+	m := make(map[K]int)
+
+	for _, e := range s {
+		m[key(e)]++
+	}
+
+	return m
+}
+
+// CountBy returns the number of elements in the provided slice for which the
+// pred function returns true.
+func CountBy[E any](s []E, pred func(e E) bool) int {
+	n := 0
+
+	for _, e := range s {
+		if pred(e) {
+			n++
+		}
+	}
+
+	return n
+}
+
+// CountByKey creates a map of keys K to the number of elements in the provided
+// slice that have that key, as determined by the key function, and for which
+// the pred function returns true. The key function is only called for elements
+// that satisfy pred, so keys that have no such elements are absent from the map.
+func CountByKey[E any, K comparable](s []E, key func(e E) K, pred func(e E) bool) map[K]int {
+	m := make(map[K]int)
+
+	for _, e := range s {
+		if pred(e) {
+			m[key(e)]++
+		}
+	}
+
+	return m
+}
+
+// RatioByKey creates a map of keys K to the fraction of the elements of the
+// provided slice that have that key, as determined by the key function. The
+// fractions sum to 1, give or take floating-point rounding errors. An empty
+// slice results in an empty map.
+func RatioByKey[E any, K comparable](s []E, key func(e E) K) map[K]float64 {
+	m := make(map[K]float64)
+
+	for k, n := range Count(s, key) {
+		m[k] = float64(n) / float64(len(s))
+	}
+
+	return m
+}
+
+// CumulativeByKey counts the elements of the provided slice per key, as
+// determined by the key function, and returns one entry per key in ascending key
+// order, holding the key, its count and the fraction of all of the elements
+// that have a key less than or equal to it. The cumulative fraction of the last
+// entry is therefore 1. An empty slice results in an empty slice.
+func CumulativeByKey[E any, K cmp.Ordered](s []E, key func(e E) K) []struct {
+	Key                K
+	Count              int
+	CumulativeFraction float64
+} {
+	counts := Count(s, key)
+
+	keys := SortedKeys(counts)
+
+	result := make([]struct {
+		Key                K
+		Count              int
+		CumulativeFraction float64
+	}, len(keys))
+
+	running := 0
+	for i, k := range keys {
+		running += counts[k]
+
+		result[i].Key = k
+		result[i].Count = counts[k]
+		result[i].CumulativeFraction = float64(running) / float64(len(s))
+	}
+
+	return result
+}
+
+// PrefixSumByKey creates a map of keys K to the running sums of the values
+// projected from the elements of each key by the value function, in the order
+// the elements appear in the provided slice. The last value of each key's slice
+// is therefore the total of that key.
+func PrefixSumByKey[E any, K comparable](s []E, key func(e E) K, value func(e E) float64) map[K][]float64 {
+	m := make(map[K][]float64)
+
+	for _, e := range s {
+		k := key(e)
+
+		sum := value(e)
+		if sums := m[k]; len(sums) > 0 {
+			sum += sums[len(sums)-1]
+		}
+
+		m[k] = append(m[k], sum)
+	}
+
+	return m
+}
+
+// ProbabilityByKey creates a map of keys K to the sum of the weights of the
+// elements of the provided slice that have that key, as determined by the key
+// and weight functions, divided by the total weight of all of the elements. The
+// weights are expected to be non-negative, in which case the values form a
+// probability distribution that sums to 1, give or take floating-point rounding
+// errors. If the total weight is zero, including when the slice is empty, the
+// map is empty.
+func ProbabilityByKey[E any, K comparable](s []E, key func(e E) K, weight func(e E) float64) map[K]float64 {
+	m := make(map[K]float64)
+	total := 0.0
+
+	for _, e := range s {
+		w := weight(e)
+
+		m[key(e)] += w
+		total += w
+	}
+
+	if total == 0 {
+		return make(map[K]float64)
+	}
+
+	for k, v := range m {
+		m[k] = v / total
+	}
+
+	return m
+}
+
+// Breakdown creates a map of keys K to the number of elements of the provided
+// slice that have that key, as determined by the key function, along with the
+// percentage of all of the elements that this number represents. The
+// percentages sum to 100, give or take floating-point rounding errors. An
+// empty slice results in an empty map.
+func Breakdown[E any, K comparable](s []E, key func(e E) K) map[K]struct {
+	Count   int
+	Percent float64
+} {
+	counts := Count(s, key)
+
+	m := make(map[K]struct {
+		Count   int
+		Percent float64
+	}, len(counts))
+
+	for k, n := range counts {
+		m[k] = struct {
+			Count   int
+			Percent float64
+		}{Count: n, Percent: float64(n) * 100 / float64(len(s))}
+	}
+
+	return m
+}
+
+// FindGaps collects the sequence numbers of the elements of the provided slice,
+// as returned by the seq function, and returns the integers missing between the
+// smallest and the largest of them, in ascending order. Repeated sequence
+// numbers are allowed. If the sequence numbers are contiguous, or if the slice
+// is empty, the result is an empty slice.
+func FindGaps[E any](s []E, seq func(e E) int) []int {
+	gaps := make([]int, 0)
+
+	if len(s) == 0 {
+		return gaps
+	}
+
+	present := make(map[int]struct{}, len(s))
+	lo, hi := seq(s[0]), seq(s[0])
+
+	for _, e := range s {
+		n := seq(e)
+
+		present[n] = struct{}{}
+		lo, hi = min(lo, n), max(hi, n)
+	}
+
+	for n := lo + 1; n < hi; n++ {
+		if _, ok := present[n]; !ok {
+			gaps = append(gaps, n)
+		}
+	}
+
+	return gaps
+}
+
+// EntropyByKey groups the elements of the provided slice by the key returned by
+// the k1 function and computes, for each group, the Shannon entropy, in bits, of
+// the distribution of the keys returned by the k2 function within the group.
+// A group whose elements all share the same k2 key has an entropy of 0, while a
+// group evenly spread across n k2 keys has the maximum entropy of log2(n).
+func EntropyByKey[E any, K1, K2 comparable](s []E, k1 func(e E) K1, k2 func(e E) K2) map[K1]float64 {
+	counts := make(map[K1]map[K2]int)
+	totals := make(map[K1]int)
+
+	for _, e := range s {
+		key1 := k1(e)
+
+		sub, ok := counts[key1]
+		if !ok {
+			sub = make(map[K2]int)
+			counts[key1] = sub
+		}
+
+		sub[k2(e)]++
+		totals[key1]++
+	}
+
+	m := make(map[K1]float64, len(counts))
+
+	for key1, sub := range counts {
+		entropy := 0.0
+
+		for _, n := range sub {
+			p := float64(n) / float64(totals[key1])
+			entropy -= p * math.Log2(p)
+		}
+
+		m[key1] = entropy
+	}
+
+	return m
+}
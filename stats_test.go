@@ -0,0 +1,327 @@
+package mapify
+
+import (
+	"math"
+	"testing"
+)
+
+// TestCount verifies that the Count function counts the elements of each key.
+func TestCount(t *testing.T) {
+	key := func(s string) byte {
+		return s[0]
+	}
+
+	for _, tc := range []struct {
+		name     string
+		input    []string
+		expected map[byte]int
+	}{
+		{
+			name:     "nil-input-slice",
+			input:    nil,
+			expected: map[byte]int{},
+		},
+		{
+			name:     "several-keys",
+			input:    []string{"apple", "banana", "avocado", "cherry", "apricot"},
+			expected: map[byte]int{'a': 3, 'b': 1, 'c': 1},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			verifyResult(t, tc.expected, Count(tc.input, key))
+		})
+	}
+}
+
+// TestCountBy verifies that the CountBy function only counts the elements that
+// satisfy the predicate.
+func TestCountBy(t *testing.T) {
+	input := []*TestUser{&testUserBob, &testUserAlice, &testUserFred, &testUserMarie}
+
+	for _, tc := range []struct {
+		name     string
+		pred     func(*TestUser) bool
+		expected int
+	}{
+		{
+			name:     "some-match",
+			pred:     func(u *TestUser) bool { return u.id%2 == 0 },
+			expected: 2,
+		},
+		{
+			name:     "none-match",
+			pred:     func(u *TestUser) bool { return u.id > 10 },
+			expected: 0,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := CountBy(input, tc.pred); result != tc.expected {
+				t.Fatalf("expected %d but got %d", tc.expected, result)
+			}
+		})
+	}
+}
+
+// TestCountByKey verifies that the CountByKey function counts the elements per
+// key and excludes the elements that do not satisfy the predicate.
+func TestCountByKey(t *testing.T) {
+	input := []string{"apple", "avocado", "banana", "blueberry", "cherry", "apricot", "b"}
+
+	result := CountByKey(input, func(s string) byte {
+		return s[0]
+	}, func(s string) bool {
+		return len(s) > 5
+	})
+
+	verifyResult(t, map[byte]int{
+		'a': 2,
+		'b': 2,
+		'c': 1,
+	}, result)
+}
+
+// TestRatioByKey verifies that the RatioByKey function computes the fraction
+// of the elements of each key and that the fractions sum to 1.
+func TestRatioByKey(t *testing.T) {
+	input := []string{"apple", "avocado", "banana", "blueberry", "cherry", "apricot", "almond", "blackberry"}
+
+	result := RatioByKey(input, func(s string) byte {
+		return s[0]
+	})
+
+	expected := map[byte]float64{
+		'a': 0.5,
+		'b': 0.375,
+		'c': 0.125,
+	}
+
+	verifyFloats(t, expected, result)
+
+	sum := 0.0
+	for _, v := range result {
+		sum += v
+	}
+
+	if math.Abs(sum-1) > floatTolerance {
+		t.Fatalf("ratios are expected to sum to 1 but summed to %f", sum)
+	}
+
+	verifyResult(t, map[byte]float64{}, RatioByKey(nil, func(s string) byte { return s[0] }))
+}
+
+// floatTolerance is the maximum difference allowed between floating-point
+// values that are expected to be equal.
+const floatTolerance = 1e-9
+
+// verifyFloats is a convenience function to verify that an actual map of keys
+// K to floating-point values matches an expected map, within floatTolerance.
+func verifyFloats[K comparable](t *testing.T, expected, actual map[K]float64) {
+	if len(actual) != len(expected) {
+		t.Logf("length of actual is expected to be %d but was %d", len(expected), len(actual))
+		t.Fail()
+	}
+
+	for k, v := range expected {
+		if av, ok := actual[k]; !ok {
+			t.Logf("actual is expected to contain %v for key %v but it did not have that key", v, k)
+			t.Fail()
+		} else if math.Abs(av-v) > floatTolerance {
+			t.Logf("actual is expected to contain %v for key %v but contained %v", v, k, av)
+			t.Fail()
+		}
+	}
+}
+
+// TestCumulativeByKey verifies that the CumulativeByKey function returns the
+// keys in ascending order with their counts and running cumulative fractions.
+func TestCumulativeByKey(t *testing.T) {
+	input := []int{3, 1, 2, 3, 3, 1, 5, 3}
+
+	result := CumulativeByKey(input, func(e int) int { return e })
+
+	expected := []struct {
+		Key                int
+		Count              int
+		CumulativeFraction float64
+	}{
+		{Key: 1, Count: 2, CumulativeFraction: 0.25},
+		{Key: 2, Count: 1, CumulativeFraction: 0.375},
+		{Key: 3, Count: 4, CumulativeFraction: 0.875},
+		{Key: 5, Count: 1, CumulativeFraction: 1},
+	}
+
+	verifySlice(t, expected, result)
+
+	if last := result[len(result)-1].CumulativeFraction; last != 1 {
+		t.Fatalf("final cumulative fraction is expected to be 1 but was %f", last)
+	}
+
+	if empty := CumulativeByKey(nil, func(e int) int { return e }); len(empty) != 0 {
+		t.Fatalf("expected no entries for an empty slice but got %v", empty)
+	}
+}
+
+// TestPrefixSumByKey verifies that the PrefixSumByKey function computes the
+// running sums of the values of each key in input order.
+func TestPrefixSumByKey(t *testing.T) {
+	type Sale struct {
+		region string
+		amount float64
+	}
+
+	input := []Sale{
+		{region: "east", amount: 10},
+		{region: "west", amount: 5},
+		{region: "east", amount: 2.5},
+		{region: "east", amount: 7.5},
+		{region: "west", amount: 1},
+	}
+
+	result := PrefixSumByKey(input, func(s Sale) string {
+		return s.region
+	}, func(s Sale) float64 {
+		return s.amount
+	})
+
+	if len(result) != 2 {
+		t.Fatalf("expected 2 keys but got %v", result)
+	}
+
+	verifySlice(t, []float64{10, 12.5, 20}, result["east"])
+	verifySlice(t, []float64{5, 6}, result["west"])
+}
+
+// TestProbabilityByKey verifies that the ProbabilityByKey function normalizes
+// the weights of each key into a distribution that sums to 1, and returns an
+// empty map when the total weight is zero.
+func TestProbabilityByKey(t *testing.T) {
+	type Sale struct {
+		region string
+		amount float64
+	}
+
+	key := func(s Sale) string { return s.region }
+	weight := func(s Sale) float64 { return s.amount }
+
+	result := ProbabilityByKey([]Sale{
+		{region: "east", amount: 30},
+		{region: "west", amount: 10},
+		{region: "east", amount: 20},
+		{region: "north", amount: 40},
+		{region: "south", amount: 0},
+	}, key, weight)
+
+	verifyFloats(t, map[string]float64{
+		"east":  0.5,
+		"west":  0.1,
+		"north": 0.4,
+		"south": 0,
+	}, result)
+
+	sum := 0.0
+	for _, v := range result {
+		sum += v
+	}
+
+	if math.Abs(sum-1) > floatTolerance {
+		t.Fatalf("probabilities are expected to sum to 1 but summed to %f", sum)
+	}
+
+	verifyFloats(t, map[string]float64{}, ProbabilityByKey([]Sale{{region: "east"}}, key, weight))
+}
+
+// TestBreakdown verifies that the Breakdown function reports the count and
+// percentage of each key.
+func TestBreakdown(t *testing.T) {
+	input := []string{"apple", "avocado", "banana", "apricot", "blueberry", "cherry", "almond", "blackberry"}
+
+	result := Breakdown(input, func(s string) byte {
+		return s[0]
+	})
+
+	type entry = struct {
+		Count   int
+		Percent float64
+	}
+
+	verifyResult(t, map[byte]entry{
+		'a': {Count: 4, Percent: 50},
+		'b': {Count: 3, Percent: 37.5},
+		'c': {Count: 1, Percent: 12.5},
+	}, result)
+
+	sum := 0.0
+	for _, v := range result {
+		sum += v.Percent
+	}
+
+	if math.Abs(sum-100) > floatTolerance {
+		t.Fatalf("percentages are expected to sum to 100 but summed to %f", sum)
+	}
+}
+
+// TestFindGaps verifies that the FindGaps function returns the sequence numbers
+// missing between the smallest and largest present ones.
+func TestFindGaps(t *testing.T) {
+	identity := func(n int) int { return n }
+
+	for _, tc := range []struct {
+		name     string
+		input    []int
+		expected []int
+	}{
+		{
+			name:     "nil-input-slice",
+			input:    nil,
+			expected: []int{},
+		},
+		{
+			name:     "contiguous",
+			input:    []int{4, 2, 3, 5, 3},
+			expected: []int{},
+		},
+		{
+			name:     "several-gaps",
+			input:    []int{10, 3, 7, 4, 12},
+			expected: []int{5, 6, 8, 9, 11},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			verifySlice(t, tc.expected, FindGaps(tc.input, identity))
+		})
+	}
+}
+
+// TestEntropyByKey verifies that the EntropyByKey function yields the maximum
+// entropy for evenly distributed groups and zero for single-valued groups.
+func TestEntropyByKey(t *testing.T) {
+	type Purchase struct {
+		customer string
+		product  string
+	}
+
+	input := []Purchase{
+		{customer: "bob", product: "apple"},
+		{customer: "alice", product: "apple"},
+		{customer: "bob", product: "pear"},
+		{customer: "alice", product: "apple"},
+		{customer: "bob", product: "plum"},
+		{customer: "fred", product: "apple"},
+		{customer: "bob", product: "kiwi"},
+		{customer: "fred", product: "pear"},
+		{customer: "fred", product: "apple"},
+		{customer: "fred", product: "apple"},
+	}
+
+	result := EntropyByKey(input, func(p Purchase) string {
+		return p.customer
+	}, func(p Purchase) string {
+		return p.product
+	})
+
+	verifyFloats(t, map[string]float64{
+		"bob":   2,
+		"alice": 0,
+		"fred":  -(0.75*math.Log2(0.75) + 0.25*math.Log2(0.25)),
+	}, result)
+}
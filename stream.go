@@ -0,0 +1,246 @@
+package mapify
+
+import (
+	"container/heap"
+	"slices"
+)
+
+// TopKTracker keeps track of the k greatest elements added to it, according to
+// a less function, without storing every element. It uses a bounded min-heap,
+// so adding an element costs O(log k).
+type TopKTracker[E any] struct {
+	k    int
+	heap *minHeap[E]
+}
+
+// NewTopKTracker creates a TopKTracker that keeps the k greatest elements, as
+// ordered by the provided less function, which reports whether a is less than
+// b. If k is less than or equal to zero, the tracker keeps no elements.
+func NewTopKTracker[E any](k int, less func(a, b E) bool) *TopKTracker[E] {
+	return &TopKTracker[E]{
+		k:    k,
+		heap: &minHeap[E]{less: less},
+	}
+}
+
+// Add offers the element e to the tracker. It is kept if fewer than k elements
+// have been kept so far or if it is greater than the least kept element, which
+// is then discarded.
+func (t *TopKTracker[E]) Add(e E) {
+	if t.k <= 0 {
+		return
+	}
+
+	if t.heap.Len() < t.k {
+		heap.Push(t.heap, e)
+		return
+	}
+
+	if t.heap.less(t.heap.elements[0], e) {
+		t.heap.elements[0] = e
+		heap.Fix(t.heap, 0)
+	}
+}
+
+// Result returns the elements kept by the tracker sorted from greatest to
+// least. The returned slice is a copy that the caller is free to modify.
+func (t *TopKTracker[E]) Result() []E {
+	result := slices.Clone(t.heap.elements)
+
+	slices.SortFunc(result, compareFunc(func(a, b E) bool {
+		return t.heap.less(b, a)
+	}))
+
+	return result
+}
+
+// minHeap is an implementation of heap.Interface that keeps the least element,
+// according to its less function, at the root.
+type minHeap[E any] struct {
+	elements []E
+	less     func(a, b E) bool
+}
+
+func (h *minHeap[E]) Len() int           { return len(h.elements) }
+func (h *minHeap[E]) Less(i, j int) bool { return h.less(h.elements[i], h.elements[j]) }
+func (h *minHeap[E]) Swap(i, j int)      { h.elements[i], h.elements[j] = h.elements[j], h.elements[i] }
+func (h *minHeap[E]) Push(x any)         { h.elements = append(h.elements, x.(E)) }
+
+func (h *minHeap[E]) Pop() any {
+	last := h.elements[len(h.elements)-1]
+	h.elements = h.elements[:len(h.elements)-1]
+
+	return last
+}
+
+// GroupFromChannel creates a map in the same way as FromSliceWithDuplicates
+// using all of the elements of the batches received from the provided channel,
+// until it is closed. The elements are appended to their slice in the order they
+// are received, so the order of the batches is preserved. GroupFromChannel
+// blocks until the channel is closed.
+func GroupFromChannel[E any, K comparable](ch <-chan []E, key func(e E) K) map[K][]E {
+	m := make(map[K][]E)
+
+	for batch := range ch {
+		for _, e := range batch {
+			k := key(e)
+
+			m[k] = append(m[k], e)
+		}
+	}
+
+	return m
+}
+
+// StreamingBalancer distributes elements across a fixed number of bins as they
+// are added, placing each one in the bin with the least total weight at that
+// time. Since it never reorders past decisions, the bins stay within the
+// weight of the heaviest element of each other, without requiring the whole
+// input to be known up front.
+type StreamingBalancer[E any] struct {
+	weight  func(e E) int
+	bins    [][]E
+	weights []int
+}
+
+// NewStreamingBalancer creates a StreamingBalancer with the provided number of
+// bins, using the weight function to determine the weight of each element. If
+// bins is less than one, a single bin is used.
+func NewStreamingBalancer[E any](bins int, weight func(e E) int) *StreamingBalancer[E] {
+	if bins < 1 {
+		bins = 1
+	}
+
+	return &StreamingBalancer[E]{
+		weight:  weight,
+		bins:    make([][]E, bins),
+		weights: make([]int, bins),
+	}
+}
+
+// Add places the element e in the bin that currently has the least total
+// weight, preferring the bin with the lowest index on ties, and returns the
+// index of that bin.
+func (b *StreamingBalancer[E]) Add(e E) int {
+	lightest := 0
+
+	for i, w := range b.weights {
+		if w < b.weights[lightest] {
+			lightest = i
+		}
+	}
+
+	b.bins[lightest] = append(b.bins[lightest], e)
+	b.weights[lightest] += b.weight(e)
+
+	return lightest
+}
+
+// Bins returns the elements of each bin, in the order they were added. The
+// returned slices are copies that the caller is free to modify.
+func (b *StreamingBalancer[E]) Bins() [][]E {
+	bins := make([][]E, len(b.bins))
+
+	for i, bin := range b.bins {
+		bins[i] = slices.Clone(bin)
+	}
+
+	return bins
+}
+
+// FanOutByKey groups the elements of the provided slice by key, as determined by
+// the key function, and returns a map of keys to channels that each receive the
+// elements of their key, in the order they appear in the provided slice. Each
+// channel has a buffer of bufferSize elements and is fed by its own goroutine,
+// which closes it once all of the key's elements have been sent. The caller must
+// drain every channel, otherwise the goroutines feeding the channels whose
+// elements do not fit in their buffer are leaked. If bufferSize is less than
+// zero, the channels are unbuffered.
+func FanOutByKey[E any, K comparable](s []E, key func(e E) K, bufferSize int) map[K]chan E {
+	bufferSize = max(bufferSize, 0)
+
+	groups := FromSliceWithDuplicates(s, key)
+	m := make(map[K]chan E, len(groups))
+
+	for k, group := range groups {
+		ch := make(chan E, bufferSize)
+		m[k] = ch
+
+		go func() {
+			defer close(ch)
+
+			for _, e := range group {
+				ch <- e
+			}
+		}()
+	}
+
+	return m
+}
+
+// FlushingGrouper groups the elements added to it in the same way as
+// FromSliceWithDuplicates, handing the grouping over to a flush function every
+// time a fixed number of elements has been accumulated, which bounds the memory
+// used when grouping unbounded streams. A FlushingGrouper is not safe for
+// concurrent use.
+type FlushingGrouper[E any, K comparable] struct {
+	key        func(e E) K
+	flushEvery int
+	flush      func(m map[K][]E) error
+	m          map[K][]E
+	n          int
+}
+
+// NewFlushingGrouper creates a FlushingGrouper that uses the key function to
+// determine the key of each element, and calls the flush function with the
+// accumulated grouping every flushEvery elements. If flushEvery is less than
+// one, the grouping is only flushed by Close.
+func NewFlushingGrouper[E any, K comparable](key func(e E) K, flushEvery int, flush func(m map[K][]E) error) *FlushingGrouper[E, K] {
+	return &FlushingGrouper[E, K]{
+		key:        key,
+		flushEvery: flushEvery,
+		flush:      flush,
+		m:          make(map[K][]E),
+	}
+}
+
+// Add adds the element e to the grouping and flushes the grouping if flushEvery
+// elements have been accumulated since the last flush. Once flushed, the
+// grouping starts over empty. If the flush function returns an error, Add
+// returns it and keeps the accumulated elements, so that the flush is attempted
+// again by the next call to Add or Close.
+func (g *FlushingGrouper[E, K]) Add(e E) error {
+	k := g.key(e)
+
+	g.m[k] = append(g.m[k], e)
+	g.n++
+
+	if g.flushEvery > 0 && g.n >= g.flushEvery {
+		return g.flushAccumulated()
+	}
+
+	return nil
+}
+
+// Close flushes the elements accumulated since the last flush, if any, and
+// returns the error returned by the flush function.
+func (g *FlushingGrouper[E, K]) Close() error {
+	if g.n == 0 {
+		return nil
+	}
+
+	return g.flushAccumulated()
+}
+
+// flushAccumulated calls the flush function with the accumulated grouping and
+// starts over with an empty grouping if it succeeds.
+func (g *FlushingGrouper[E, K]) flushAccumulated() error {
+	if err := g.flush(g.m); err != nil {
+		return err
+	}
+
+	g.m = make(map[K][]E)
+	g.n = 0
+
+	return nil
+}
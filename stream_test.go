@@ -0,0 +1,242 @@
+package mapify
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestTopKTracker verifies that the TopKTracker only keeps the k greatest
+// elements added to it and returns them from greatest to least.
+func TestTopKTracker(t *testing.T) {
+	byID := func(a, b *TestUser) bool {
+		return a.id < b.id
+	}
+
+	for _, tc := range []struct {
+		name     string
+		k        int
+		input    []*TestUser
+		expected []*TestUser
+	}{
+		{
+			name:     "more-than-k",
+			k:        2,
+			input:    []*TestUser{&testUserAlice, &testUserMarie, &testUserBob, &testUserFred},
+			expected: []*TestUser{&testUserMarie, &testUserFred},
+		},
+		{
+			name:     "fewer-than-k",
+			k:        5,
+			input:    []*TestUser{&testUserFred, &testUserBob},
+			expected: []*TestUser{&testUserFred, &testUserBob},
+		},
+		{
+			name:     "zero-k",
+			k:        0,
+			input:    []*TestUser{&testUserFred, &testUserBob},
+			expected: []*TestUser{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tracker := NewTopKTracker(tc.k, byID)
+
+			for _, u := range tc.input {
+				tracker.Add(u)
+			}
+
+			verifySlice(t, tc.expected, tracker.Result())
+		})
+	}
+}
+
+// TestGroupFromChannel verifies that the GroupFromChannel function groups the
+// elements of every batch received before the channel is closed.
+func TestGroupFromChannel(t *testing.T) {
+	ch := make(chan []*TestUser)
+
+	go func() {
+		defer close(ch)
+
+		ch <- []*TestUser{&testUserBob, &testUserAlice}
+		ch <- nil
+		ch <- []*TestUser{&testUserFred, &testUserMarie}
+	}()
+
+	result := GroupFromChannel(ch, func(u *TestUser) int {
+		return u.id % 2
+	})
+
+	if len(result) != 2 {
+		t.Fatalf("expected 2 keys but got %d", len(result))
+	}
+
+	verifySlice(t, []*TestUser{&testUserAlice, &testUserMarie}, result[0])
+	verifySlice(t, []*TestUser{&testUserBob, &testUserFred}, result[1])
+}
+
+// TestStreamingBalancer verifies that the StreamingBalancer always places an
+// element in the lightest bin, which keeps the bins balanced as elements of
+// varying weight are added.
+func TestStreamingBalancer(t *testing.T) {
+	weight := func(e int) int { return e }
+
+	balancer := NewStreamingBalancer(3, weight)
+
+	var chosen []int
+	for _, e := range []int{5, 3, 8, 2, 2, 7, 1, 4, 6, 3} {
+		chosen = append(chosen, balancer.Add(e))
+	}
+
+	verifySlice(t, []int{0, 1, 2, 1, 0, 1, 0, 0, 2, 0}, chosen)
+
+	bins := balancer.Bins()
+	if len(bins) != 3 {
+		t.Fatalf("expected 3 bins but got %d", len(bins))
+	}
+
+	verifySlice(t, []int{5, 2, 1, 4, 3}, bins[0])
+	verifySlice(t, []int{3, 2, 7}, bins[1])
+	verifySlice(t, []int{8, 6}, bins[2])
+
+	least, most := -1, -1
+	for _, bin := range bins {
+		total := 0
+		for _, e := range bin {
+			total += weight(e)
+		}
+
+		if least == -1 || total < least {
+			least = total
+		}
+
+		if total > most {
+			most = total
+		}
+	}
+
+	if most-least > 8 {
+		t.Fatalf("bins are expected to be within the heaviest element of each other but ranged from %d to %d", least, most)
+	}
+}
+
+// TestFanOutByKey verifies that each channel returned by the FanOutByKey
+// function receives exactly the elements of its key, in order, and is closed.
+func TestFanOutByKey(t *testing.T) {
+	input := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	result := FanOutByKey(input, func(e int) int { return e % 3 }, 1)
+
+	if len(result) != 3 {
+		t.Fatalf("expected 3 channels but got %d", len(result))
+	}
+
+	for k, expected := range map[int][]int{
+		0: {3, 6, 9},
+		1: {1, 4, 7, 10},
+		2: {2, 5, 8},
+	} {
+		var received []int
+		for e := range result[k] {
+			received = append(received, e)
+		}
+
+		verifySlice(t, expected, received)
+	}
+
+	unbuffered := FanOutByKey(input, func(e int) int { return 0 }, -1)
+	if c := cap(unbuffered[0]); c != 0 {
+		t.Fatalf("expected a negative buffer size to give an unbuffered channel but got a capacity of %d", c)
+	}
+
+	var received []int
+	for e := range unbuffered[0] {
+		received = append(received, e)
+	}
+
+	verifySlice(t, input, received)
+}
+
+// TestFlushingGrouper verifies that the FlushingGrouper flushes the grouping
+// every flushEvery elements and flushes the remainder on Close.
+func TestFlushingGrouper(t *testing.T) {
+	var flushed []map[int][]int
+
+	grouper := NewFlushingGrouper(func(e int) int {
+		return e % 2
+	}, 3, func(m map[int][]int) error {
+		flushed = append(flushed, m)
+		return nil
+	})
+
+	for e := 1; e <= 7; e++ {
+		if err := grouper.Add(e); err != nil {
+			t.Fatalf("unexpected error adding %d: %v", e, err)
+		}
+
+		if expected := e / 3; len(flushed) != expected {
+			t.Fatalf("expected %d flushes after adding %d but got %d", expected, e, len(flushed))
+		}
+	}
+
+	if err := grouper.Close(); err != nil {
+		t.Fatalf("unexpected error closing: %v", err)
+	}
+
+	if len(flushed) != 3 {
+		t.Fatalf("expected 3 flushes but got %d", len(flushed))
+	}
+
+	for i, expected := range []map[int][]int{
+		{0: {2}, 1: {1, 3}},
+		{0: {4, 6}, 1: {5}},
+		{1: {7}},
+	} {
+		if len(flushed[i]) != len(expected) {
+			t.Fatalf("flush %d is expected to have %d keys but had %v", i, len(expected), flushed[i])
+		}
+
+		for k, v := range expected {
+			verifySlice(t, v, flushed[i][k])
+		}
+	}
+}
+
+// TestFlushingGrouper_Error verifies that the FlushingGrouper keeps the
+// accumulated elements when the flush function fails and retries on Close.
+func TestFlushingGrouper_Error(t *testing.T) {
+	errFlush := errors.New("flush failed")
+	fail := true
+
+	var flushed map[string][]string
+
+	grouper := NewFlushingGrouper(func(s string) string {
+		return s[:1]
+	}, 2, func(m map[string][]string) error {
+		if fail {
+			return errFlush
+		}
+
+		flushed = m
+		return nil
+	})
+
+	if err := grouper.Add("apple"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := grouper.Add("avocado"); !errors.Is(err, errFlush) {
+		t.Fatalf("expected %v but got %v", errFlush, err)
+	}
+
+	fail = false
+
+	if err := grouper.Close(); err != nil {
+		t.Fatalf("unexpected error closing: %v", err)
+	}
+
+	verifySlice(t, []string{"apple", "avocado"}, flushed["a"])
+
+	if err := grouper.Close(); err != nil {
+		t.Fatalf("unexpected error closing an empty grouper: %v", err)
+	}
+}
@@ -0,0 +1,139 @@
+package mapify
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"time"
+)
+
+// GroupWithRetention groups the elements of the provided slice in the same way
+// as FromSliceWithDuplicates while applying two retention rules. First, the
+// elements whose timestamp, as returned by the timestamp function, is more than
+// maxAge before now are dropped. Then, only the maxPerKey most recent elements
+// of each key are kept, the elements with equal timestamps being ranked by
+// their position in the provided slice. The kept elements remain in the order
+// they appear in the provided slice, and keys left without elements are absent
+// from the map. If maxPerKey is less than one, the number of elements per key
+// is not limited.
+func GroupWithRetention[E any, K comparable](s []E, key func(e E) K, timestamp func(e E) time.Time, maxAge time.Duration, now time.Time, maxPerKey int) map[K][]E {
+	cutoff := now.Add(-maxAge)
+	m := make(map[K][]E)
+
+	for _, e := range s {
+		if timestamp(e).Before(cutoff) {
+			continue
+		}
+
+		k := key(e)
+
+		m[k] = append(m[k], e)
+	}
+
+	if maxPerKey < 1 {
+		return m
+	}
+
+	for k, bucket := range m {
+		if len(bucket) <= maxPerKey {
+			continue
+		}
+
+		order := indexRange(len(bucket))
+		slices.SortStableFunc(order, func(a, b int) int {
+			return timestamp(bucket[b]).Compare(timestamp(bucket[a]))
+		})
+
+		keep := order[:maxPerKey]
+		slices.Sort(keep)
+
+		kept := make([]E, 0, maxPerKey)
+		for _, i := range keep {
+			kept = append(kept, bucket[i])
+		}
+
+		m[k] = kept
+	}
+
+	return m
+}
+
+// EventRate counts the elements of the provided slice per interval of time,
+// based on the timestamp returned by the timestamp function. It returns a dense
+// series of consecutive intervals covering the range from from, inclusively, to
+// to, exclusively, including the intervals that have no elements. The last
+// interval extends past to when the range is not a multiple of interval.
+// Elements whose timestamp is before from or not before to are ignored. If
+// interval is not positive, to is not after from, or the range is too long to
+// be represented by a time.Duration, about 292 years, the series is empty.
+func EventRate[E any](s []E, timestamp func(e E) time.Time, interval time.Duration, from, to time.Time) []struct {
+	BucketStart time.Time
+	Count       int
+} {
+	// Sub saturates at the maximum duration, so a range that long cannot be
+	// told apart from a longer one and is treated as unrepresentable.
+	span := to.Sub(from)
+	if interval <= 0 || span <= 0 || span == math.MaxInt64 {
+		return []struct {
+			BucketStart time.Time
+			Count       int
+		}{}
+	}
+
+	n := int(span / interval)
+	if span%interval != 0 {
+		n++
+	}
+
+	series := make([]struct {
+		BucketStart time.Time
+		Count       int
+	}, n)
+
+	for i := range series {
+		series[i].BucketStart = from.Add(time.Duration(i) * interval)
+	}
+
+	for _, e := range s {
+		ts := timestamp(e)
+		if ts.Before(from) || !ts.Before(to) {
+			continue
+		}
+
+		series[ts.Sub(from)/interval].Count++
+	}
+
+	return series
+}
+
+// TimeBucketedSample groups the elements of the provided slice into time
+// buckets, keyed by their timestamp, as returned by the timestamp function,
+// truncated to a multiple of bucket, and keeps a uniform random sample of at
+// most sampleSize elements per bucket. The samples are drawn with reservoir
+// sampling using the provided random number generator, which is consumed in the
+// order of the elements, so a generator seeded with the same value always
+// produces the same samples. The bucket keys are in UTC, so timestamps of the
+// same instant share a bucket whatever their location. If bucket is not
+// positive, each distinct instant forms its own bucket.
+func TimeBucketedSample[E any](s []E, timestamp func(e E) time.Time, bucket time.Duration, sampleSize int, rng *rand.Rand) map[time.Time][]E {
+	reservoirs := make(map[time.Time]*reservoir[E])
+
+	for _, e := range s {
+		k := timestamp(e).Truncate(bucket).UTC()
+
+		r, ok := reservoirs[k]
+		if !ok {
+			r = &reservoir[E]{size: sampleSize, sample: make([]E, 0)}
+			reservoirs[k] = r
+		}
+
+		r.add(e, rng)
+	}
+
+	m := make(map[time.Time][]E, len(reservoirs))
+	for k, r := range reservoirs {
+		m[k] = r.sample
+	}
+
+	return m
+}
@@ -0,0 +1,178 @@
+package mapify
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// TestGroupWithRetention verifies that the GroupWithRetention function drops
+// the elements that are too old and keeps only the most recent elements of each
+// key.
+func TestGroupWithRetention(t *testing.T) {
+	type Event struct {
+		source string
+		name   string
+		at     time.Time
+	}
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	input := []Event{
+		{source: "a", name: "a-old", at: now.Add(-2 * time.Hour)},
+		{source: "a", name: "a-1", at: now.Add(-50 * time.Minute)},
+		{source: "b", name: "b-old", at: now.Add(-61 * time.Minute)},
+		{source: "a", name: "a-3", at: now.Add(-10 * time.Minute)},
+		{source: "a", name: "a-2", at: now.Add(-30 * time.Minute)},
+		{source: "b", name: "b-1", at: now.Add(-time.Hour)},
+		{source: "c", name: "c-old", at: now.Add(-3 * time.Hour)},
+	}
+
+	key := func(e Event) string { return e.source }
+	timestamp := func(e Event) time.Time { return e.at }
+	names := func(events []Event) []string {
+		var n []string
+		for _, e := range events {
+			n = append(n, e.name)
+		}
+
+		return n
+	}
+
+	for _, tc := range []struct {
+		name      string
+		maxPerKey int
+		expected  map[string][]string
+	}{
+		{
+			name:      "age-and-count",
+			maxPerKey: 2,
+			expected: map[string][]string{
+				"a": {"a-3", "a-2"},
+				"b": {"b-1"},
+			},
+		},
+		{
+			name:      "age-only",
+			maxPerKey: 0,
+			expected: map[string][]string{
+				"a": {"a-1", "a-3", "a-2"},
+				"b": {"b-1"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result := GroupWithRetention(input, key, timestamp, time.Hour, now, tc.maxPerKey)
+
+			if len(result) != len(tc.expected) {
+				t.Fatalf("expected %d keys but got %d", len(tc.expected), len(result))
+			}
+
+			for k, v := range tc.expected {
+				verifySlice(t, v, names(result[k]))
+			}
+		})
+	}
+}
+
+// TestEventRate verifies that the EventRate function produces a dense series of
+// counts per interval, including intervals without any elements.
+func TestEventRate(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(50 * time.Minute)
+
+	input := []time.Time{
+		from.Add(-time.Minute),
+		from,
+		from.Add(5 * time.Minute),
+		from.Add(31 * time.Minute),
+		from.Add(45 * time.Minute),
+		from.Add(49 * time.Minute),
+		to,
+	}
+
+	result := EventRate(input, func(ts time.Time) time.Time { return ts }, 10*time.Minute, from, to)
+
+	expected := []int{2, 0, 0, 1, 2}
+	if len(result) != len(expected) {
+		t.Fatalf("expected %d intervals but got %d", len(expected), len(result))
+	}
+
+	for i, r := range result {
+		if start := from.Add(time.Duration(i) * 10 * time.Minute); !r.BucketStart.Equal(start) {
+			t.Logf("interval %d is expected to start at %v but started at %v", i, start, r.BucketStart)
+			t.Fail()
+		}
+
+		if r.Count != expected[i] {
+			t.Logf("interval %d is expected to have %d elements but had %d", i, expected[i], r.Count)
+			t.Fail()
+		}
+	}
+
+	if empty := EventRate(input, func(ts time.Time) time.Time { return ts }, 0, from, to); len(empty) != 0 {
+		t.Fatalf("expected no intervals for a zero interval but got %v", empty)
+	}
+
+	first := time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC)
+	last := time.Date(3000, 1, 1, 0, 0, 0, 0, time.UTC)
+	if empty := EventRate(input, func(ts time.Time) time.Time { return ts }, time.Hour, first, last); len(empty) != 0 {
+		t.Fatalf("expected no intervals for a range longer than a time.Duration but got %d", len(empty))
+	}
+
+	longest := from.Add(math.MaxInt64 - 1)
+	if series := EventRate(input, func(ts time.Time) time.Time { return ts }, math.MaxInt64/2, from, longest); len(series) != 2 {
+		t.Fatalf("expected 2 intervals for the longest representable range but got %d", len(series))
+	}
+}
+
+// TestTimeBucketedSample verifies that the TimeBucketedSample function keeps a
+// bounded sample per time bucket, reproducibly for a seeded random number
+// generator.
+func TestTimeBucketedSample(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var input []time.Time
+	for i := 0; i < 180; i++ {
+		input = append(input, start.Add(time.Duration(i)*time.Second))
+	}
+
+	input = append(input, start.Add(5*time.Minute))
+
+	identity := func(ts time.Time) time.Time { return ts }
+
+	first := TimeBucketedSample(input, identity, time.Minute, 5, rand.New(rand.NewSource(11)))
+	second := TimeBucketedSample(input, identity, time.Minute, 5, rand.New(rand.NewSource(11)))
+
+	if len(first) != 4 {
+		t.Fatalf("expected 4 buckets but got %d", len(first))
+	}
+
+	for i, size := range []int{5, 5, 5, 0, 0, 1} {
+		k := start.Add(time.Duration(i) * time.Minute)
+
+		if len(first[k]) != size {
+			t.Logf("bucket %v is expected to have %d elements but had %d", k, size, len(first[k]))
+			t.Fail()
+		}
+
+		for _, ts := range first[k] {
+			if !ts.Truncate(time.Minute).Equal(k) {
+				t.Logf("bucket %v is not expected to contain %v", k, ts)
+				t.Fail()
+			}
+		}
+
+		verifySlice(t, first[k], second[k])
+	}
+
+	mixed := TimeBucketedSample([]time.Time{
+		start.Add(30 * time.Minute),
+		start.Add(30 * time.Minute).In(time.FixedZone("UTC+1", 3600)),
+	}, identity, time.Hour, 5, rand.New(rand.NewSource(11)))
+
+	if len(mixed) != 1 || len(mixed[start]) != 2 {
+		t.Fatalf("expected a single UTC bucket with 2 elements but got %v", mixed)
+	}
+}